@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"Planner/models"
+	"Planner/services"
+)
+
+// CheckHandler maneja las solicitudes a la ruta /planner/check
+func CheckHandler(w http.ResponseWriter, r *http.Request) {
+	serveSchedules(w, r, func(ctx context.Context, schedules []models.ScheduleModel, request models.PlannerRequest) (any, error) {
+		return services.CheckProposal(ctx, schedules, request.Options)
+	})
+}
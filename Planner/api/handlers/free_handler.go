@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"Planner/models"
+	"Planner/services"
+)
+
+// FreeResponse es la estructura que representa la respuesta para la ruta /planner/free
+type FreeResponse struct {
+	Free []models.UserStatus `json:"free"` // Usuarios libres en el momento consultado
+	Busy []models.UserStatus `json:"busy"` // Usuarios ocupados en el momento consultado
+}
+
+// FreeHandler maneja las solicitudes a la ruta /planner/free
+func FreeHandler(w http.ResponseWriter, r *http.Request) {
+	serveSchedules(w, r, func(ctx context.Context, schedules []models.ScheduleModel, request models.PlannerRequest) (any, error) {
+		free, busy, err := services.GetUsersStatus(ctx, schedules, request.Options)
+		if err != nil {
+			return nil, err
+		}
+		return FreeResponse{Free: free, Busy: busy}, nil
+	})
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"Planner/models"
+	"Planner/services"
+)
+
+// NextResponse es la estructura que representa la respuesta para la ruta /planner/next
+type NextResponse struct {
+	Slots []models.PlannerEvent `json:"slots"` // Próximos slots, del más cercano al más lejano
+}
+
+// NextHandler maneja las solicitudes a la ruta /planner/next
+func NextHandler(w http.ResponseWriter, r *http.Request) {
+	serveSchedules(w, r, func(ctx context.Context, schedules []models.ScheduleModel, request models.PlannerRequest) (any, error) {
+		slots, err := services.FindNextSlots(ctx, schedules, request.Options)
+		if err != nil {
+			return nil, err
+		}
+		return NextResponse{Slots: slots}, nil
+	})
+}
@@ -1,10 +1,11 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
-	"strconv"
+	"time"
 
 	"Planner/api/constants"
 	"Planner/models"
@@ -14,85 +15,85 @@ import (
 // PlannerResponse es la estructura que representa la respuesta para la ruta /planner
 type PlannerResponse []models.PlannerEvent
 
+// Tiempo límite por defecto del cálculo de una solicitud
+const defaultPlannerTimeout = 10 * time.Second
+
 // PlannerHandler maneja las solicitudes a la ruta /planner
 func PlannerHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Decodificar el cuerpo de la solicitud en un PlannerRequest
-	plannerRequest, ok := r.Context().Value(constants.ContextKey{Key: "Planner"}).(map[string][]models.Event)
+	plannerRequest, ok := r.Context().Value(constants.ContextKey{Key: "Planner"}).(models.PlannerRequest)
 	if !ok {
 		http.Error(w, "Error while decoding request body in handler", http.StatusInternalServerError)
 		return
 	}
 
-	// Convertir PlannerRequest a un mapa con el ID del usuario y valor ScheduleModel
-	var userSchedules []models.ScheduleModel
-	for userID, events := range plannerRequest {
-		// Crear ScheduleModel para el usuario actual
-		userSchedule := models.ScheduleModel{
-			ID: userID,
+	// Convertir los intervalos semiabiertos de la versión 2 a los intervalos cerrados que usan los servicios
+	var err error
+	if plannerRequest.Version >= models.HalfOpenVersion {
+		if plannerRequest, err = services.ConvertHalfOpenRequest(plannerRequest); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
+	}
 
-		// Convertir los eventos a TimeBlock y agregarlos al ScheduleModel
-		for _, event := range events {
-			// Convertir la hora de inicio y fin a minutos (asumiendo que están en formato "HHMM")
-			startHour := event.StartTime[:2]
-			startMinute := event.StartTime[2:]
-			endHour := event.EndTime[:2]
-			endMinute := event.EndTime[2:]
-
-			startHourInt, err := strconv.Atoi(startHour)
-			if err != nil {
-				http.Error(w, "Error when converting start hour to int", http.StatusBadRequest)
-			}
-			startMinuteInt, err := strconv.Atoi(startMinute)
-			if err != nil {
-				http.Error(w, "Error when converting start minute to int", http.StatusBadRequest)
-			}
-			endHourInt, err := strconv.Atoi(endHour)
-			if err != nil {
-				http.Error(w, "Error when converting end hour to int", http.StatusBadRequest)
-			}
-			endMinuteInt, err := strconv.Atoi(endMinute)
-			if err != nil {
-				http.Error(w, "Error when converting end minute to int", http.StatusBadRequest)
-			}
+	// Limitar el cálculo al tiempo de la solicitud, deteniéndolo también si el cliente se desconecta
+	ctx, cancel := newPlannerContext(r, plannerRequest.Options)
+	defer cancel()
 
-			// Calcular los minutos totales desde la medianoche para la hora de inicio y fin
-			startTimeMinutes := (60 * startHourInt) + startMinuteInt
-			endTimeMinutes := (60 * endHourInt) + endMinuteInt
+	// Convertir los eventos de cada usuario en su ScheduleModel
+	userSchedules, err := services.BuildSchedules(ctx, plannerRequest)
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
 
-			// Crear un TimeBlock con la hora de inicio y fin convertidas a minutos
-			timeBlock := models.TimeBlock{
-				StartMinute: startTimeMinutes,
-				EndMinute:   endTimeMinutes,
-			}
+	// En el formato de mapa de calor, responder con la cantidad de usuarios libres en cada intervalo de cada día
+	if plannerRequest.Options.Heatmap != nil {
+		heatmap, err := services.GetAvailabilityHeatmap(ctx, userSchedules, plannerRequest.Options)
+		if err != nil {
+			writePlannerError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(heatmap)
+		return
+	}
 
-			// Determinar el día de la semana y agregar el TimeBlock al ScheduleModel correspondiente
-			switch event.DayOfWeek {
-			case "l":
-				userSchedule.Monday = append(userSchedule.Monday, timeBlock)
-			case "m":
-				userSchedule.Tuesday = append(userSchedule.Tuesday, timeBlock)
-			case "i":
-				userSchedule.Wednesday = append(userSchedule.Wednesday, timeBlock)
-			case "j":
-				userSchedule.Thursday = append(userSchedule.Thursday, timeBlock)
-			case "v":
-				userSchedule.Friday = append(userSchedule.Friday, timeBlock)
-			case "s":
-				userSchedule.Saturday = append(userSchedule.Saturday, timeBlock)
-			case "d":
-				userSchedule.Sunday = append(userSchedule.Sunday, timeBlock)
-			default:
-				http.Error(w, fmt.Sprintf("Invalid day of week '%s'", event.DayOfWeek), http.StatusBadRequest)
+	// En el formato de ventanas por grupo, responder con las ventanas maximales de cada grupo de asistentes
+	if plannerRequest.Options.Coalesce != nil {
+		attendeeWindows, err := services.GetAttendeeWindows(ctx, userSchedules, plannerRequest.Options)
+		if err != nil {
+			writePlannerError(w, err)
+			return
+		}
+		if plannerRequest.Version >= models.HalfOpenVersion {
+			for i := range attendeeWindows {
+				if attendeeWindows[i].Windows, err = services.ConvertToHalfOpenEvents(attendeeWindows[i].Windows); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
 			}
 		}
-		// Agregar el ScheduleModel al array
-		userSchedules = append(userSchedules, userSchedule)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(attendeeWindows)
+		return
 	}
 
 	// Llamar al servicio GetAvailableTimeSlots
-	availableSlots := services.GetAvailableTimeSlots(userSchedules)
+	availableSlots, err := services.GetAvailableTimeSlots(ctx, userSchedules, plannerRequest.Options)
+	if err != nil {
+		writePlannerError(w, err)
+		return
+	}
+
+	// Responder con intervalos semiabiertos en la versión 2
+	if plannerRequest.Version >= models.HalfOpenVersion {
+		if availableSlots, err = services.ConvertToHalfOpenEvents(availableSlots); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 
 	// Establecer el tipo de contenido de la respuesta como JSON
 	w.Header().Set("Content-Type", "application/json")
@@ -100,3 +101,74 @@ func PlannerHandler(w http.ResponseWriter, r *http.Request) {
 	// Codificar la respuesta en JSON y escribirla en el cuerpo de la respuesta
 	json.NewEncoder(w).Encode(availableSlots)
 }
+
+// scheduleService calcula la respuesta de una ruta a partir de los horarios de los usuarios y la solicitud
+type scheduleService func(ctx context.Context, schedules []models.ScheduleModel, request models.PlannerRequest) (any, error)
+
+// serveSchedules maneja una solicitud validada por los middlewares: dentro del tiempo límite de la solicitud,
+// convierte los eventos de cada usuario en su ScheduleModel y llama a service, y responde con su resultado en JSON
+func serveSchedules(w http.ResponseWriter, r *http.Request, service scheduleService) {
+	// Obtener el PlannerRequest almacenado por los middlewares
+	plannerRequest, ok := r.Context().Value(constants.ContextKey{Key: "Planner"}).(models.PlannerRequest)
+	if !ok {
+		http.Error(w, "Error while decoding request body in handler", http.StatusInternalServerError)
+		return
+	}
+
+	// Limitar el cálculo al tiempo de la solicitud, deteniéndolo también si el cliente se desconecta
+	ctx, cancel := newPlannerContext(r, plannerRequest.Options)
+	defer cancel()
+
+	// Convertir los eventos de cada usuario en su ScheduleModel
+	userSchedules, err := services.BuildSchedules(ctx, plannerRequest)
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	response, err := service(ctx, userSchedules, plannerRequest)
+	if err != nil {
+		writePlannerError(w, err)
+		return
+	}
+
+	// Establecer el tipo de contenido de la respuesta como JSON
+	w.Header().Set("Content-Type", "application/json")
+
+	// Codificar la respuesta en JSON y escribirla en el cuerpo de la respuesta
+	json.NewEncoder(w).Encode(response)
+}
+
+// newPlannerContext crea el contexto del cálculo a partir del contexto de la solicitud, que se cancela si el cliente
+// se desconecta, con el tiempo límite de las opciones o el tiempo por defecto
+func newPlannerContext(r *http.Request, options models.PlannerOptions) (context.Context, context.CancelFunc) {
+	timeout := defaultPlannerTimeout
+	if options.Timeout > 0 {
+		timeout = time.Duration(options.Timeout) * time.Millisecond
+	}
+	return context.WithTimeout(r.Context(), timeout)
+}
+
+// writeScheduleError responde con el estado HTTP que corresponde al error de BuildSchedules: un evento inválido
+// es un error de la solicitud, a menos que la expansión de los eventos recurrentes se haya detenido
+func writeScheduleError(w http.ResponseWriter, err error) {
+	var timeoutErr *services.TimeoutError
+	if errors.As(err, &timeoutErr) || errors.Is(err, context.Canceled) {
+		writePlannerError(w, err)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+// writePlannerError responde con el estado HTTP que corresponde al error de un servicio de planificación
+func writePlannerError(w http.ResponseWriter, err error) {
+	var timeoutErr *services.TimeoutError
+	switch {
+	case errors.As(err, &timeoutErr):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case errors.Is(err, context.Canceled):
+		// El cliente se desconectó, así que no hay a quién responder
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
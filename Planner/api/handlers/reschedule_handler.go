@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"Planner/models"
+	"Planner/services"
+)
+
+// RescheduleHandler maneja las solicitudes a la ruta /planner/reschedule
+func RescheduleHandler(w http.ResponseWriter, r *http.Request) {
+	serveSchedules(w, r, func(ctx context.Context, schedules []models.ScheduleModel, request models.PlannerRequest) (any, error) {
+		// FindReschedule también necesita los eventos de la solicitud para quitar los que se mueven
+		return services.FindReschedule(ctx, request, schedules)
+	})
+}
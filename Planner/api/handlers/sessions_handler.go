@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"Planner/models"
+	"Planner/services"
+)
+
+// SessionsResponse es la estructura que representa la respuesta para la ruta /planner/sessions
+type SessionsResponse struct {
+	Sessions        []models.PlannerEvent `json:"sessions"`
+	TotalAttendance int                   `json:"totalAttendance"` // Suma de los asistentes de todas las sesiones
+}
+
+// SessionsHandler maneja las solicitudes a la ruta /planner/sessions
+func SessionsHandler(w http.ResponseWriter, r *http.Request) {
+	serveSchedules(w, r, func(ctx context.Context, schedules []models.ScheduleModel, request models.PlannerRequest) (any, error) {
+		sessions, err := services.FindRecurringSessions(ctx, schedules, request.Options)
+		if err != nil {
+			return nil, err
+		}
+		response := SessionsResponse{Sessions: sessions}
+		for _, session := range response.Sessions {
+			response.TotalAttendance += session.UsersAvailable
+		}
+		return response, nil
+	})
+}
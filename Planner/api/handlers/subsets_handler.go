@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"Planner/models"
+	"Planner/services"
+)
+
+// SubsetsResponse es la estructura que representa la respuesta para la ruta /planner/subsets
+type SubsetsResponse struct {
+	Subsets []models.AttendeeSubset `json:"subsets"`
+}
+
+// SubsetsHandler maneja las solicitudes a la ruta /planner/subsets
+func SubsetsHandler(w http.ResponseWriter, r *http.Request) {
+	serveSchedules(w, r, func(ctx context.Context, schedules []models.ScheduleModel, request models.PlannerRequest) (any, error) {
+		subsets, err := services.FindAttendeeSubsets(ctx, schedules, request.Options)
+		if err != nil {
+			return nil, err
+		}
+		return SubsetsResponse{Subsets: subsets}, nil
+	})
+}
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"Planner/models"
+)
+
+// ValidateCheckMiddleware es un middleware que valida la reunión propuesta a verificar. Debe ejecutarse después de
+// ValidatePlannerBodyMiddleware, que almacena la solicitud en el contexto
+func ValidateCheckMiddleware(next http.Handler) http.Handler {
+	return validateRouteOptions(next, func(request models.PlannerRequest) error {
+		return validateProposalOptions(request.Options)
+	})
+}
+
+// validateProposalOptions valida las horas de la reunión propuesta y su día de la semana, o su fecha si la solicitud
+// planifica sobre un rango de fechas
+func validateProposalOptions(options models.PlannerOptions) error {
+	proposal := options.Proposal
+	if proposal == nil {
+		return fmt.Errorf("Missing required option proposal in request body")
+	}
+	if !isValidTimeFormat(proposal.StartTime, proposal.EndTime) || !isValidTimeRange(proposal.StartTime, proposal.EndTime, 1) ||
+		!isValidMinutes(proposal.StartTime, proposal.EndTime) || proposal.StartTime == proposal.EndTime {
+		return fmt.Errorf("Invalid proposal time range '%s' to '%s'", proposal.StartTime, proposal.EndTime)
+	}
+	if err := validateDayOrDate("proposal", proposal.DayOfWeek, proposal.Date, options.DateRange); err != nil {
+		return err
+	}
+
+	// Con un rango de fechas, una reunión que cruza la medianoche no puede continuar después de la última fecha
+	if options.DateRange != nil && proposal.EndTime < proposal.StartTime && proposal.Date == options.DateRange.To {
+		return fmt.Errorf("Invalid proposal time range '%s' to '%s', it must end within the dateRange", proposal.StartTime, proposal.EndTime)
+	}
+	return nil
+}
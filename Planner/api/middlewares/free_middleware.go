@@ -0,0 +1,62 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"Planner/models"
+	"Planner/services"
+)
+
+// ValidateFreeMiddleware es un middleware que valida el momento de la consulta de quién está libre. Debe ejecutarse
+// después de ValidatePlannerBodyMiddleware, que almacena la solicitud en el contexto
+func ValidateFreeMiddleware(next http.Handler) http.Handler {
+	return validateRouteOptions(next, func(request models.PlannerRequest) error {
+		return validateAtOptions(request.Options)
+	})
+}
+
+// validateAtOptions valida la hora y el día de la semana del momento consultado, o su fecha si la solicitud
+// planifica sobre un rango de fechas
+func validateAtOptions(options models.PlannerOptions) error {
+	at := options.At
+	if at == nil {
+		return fmt.Errorf("Missing required option at in request body")
+	}
+	if !isValidTimeFormat(at.Time, at.Time) || !isValidTimeRange(at.Time, at.Time, 1) || !isValidMinutes(at.Time, at.Time) {
+		return fmt.Errorf("Invalid at time '%s', it must have the format hhmm", at.Time)
+	}
+
+	return validateDayOrDate("at", at.DayOfWeek, at.Date, options.DateRange)
+}
+
+// validateDayOrDate valida el día de la semana de una opción sin rango de fechas, o su fecha dentro del rango si la
+// solicitud planifica sobre fechas. El rango ya fue validado y name identifica la opción en los mensajes de error
+func validateDayOrDate(name string, dayOfWeek string, date string, dateRange *models.DateRange) error {
+	// Sin un rango de fechas, la opción es un día de la semana genérica
+	if dateRange == nil {
+		if date != "" {
+			return fmt.Errorf("Invalid %s date '%s', dates require the dateRange option", name, date)
+		}
+		if !isValidDayOfWeek(dayOfWeek) {
+			return fmt.Errorf("Invalid %s day of week '%s'", name, dayOfWeek)
+		}
+		return nil
+	}
+
+	// Con un rango de fechas, la opción es una fecha del rango
+	parsedDate, err := time.Parse(services.DateLayout, date)
+	if err != nil {
+		return fmt.Errorf("Invalid %s date '%s', it must have the format YYYY-MM-DD", name, date)
+	}
+	from, _ := time.Parse(services.DateLayout, dateRange.From)
+	to, _ := time.Parse(services.DateLayout, dateRange.To)
+	if parsedDate.Before(from) || parsedDate.After(to) {
+		return fmt.Errorf("Invalid %s date '%s', it must be within the dateRange", name, date)
+	}
+	if dayOfWeek != "" && dayOfWeek != services.GetDayOfWeekOfDate(date) {
+		return fmt.Errorf("Day of week '%s' does not match date '%s'", dayOfWeek, date)
+	}
+	return nil
+}
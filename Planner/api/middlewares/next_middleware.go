@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"Planner/models"
+)
+
+// ValidateNextMiddleware es un middleware que valida el momento, la duración y el número de los próximos slots.
+// Debe ejecutarse después de ValidatePlannerBodyMiddleware, que almacena la solicitud en el contexto
+func ValidateNextMiddleware(next http.Handler) http.Handler {
+	return validateRouteOptions(next, func(request models.PlannerRequest) error {
+		return validateNextOptions(request.Options)
+	})
+}
+
+// validateNextOptions valida el momento desde el que se buscan los slots, la duración de la reunión y cuántos
+// slots retornar
+func validateNextOptions(options models.PlannerOptions) error {
+	if err := validateAtOptions(options); err != nil {
+		return err
+	}
+	if options.Duration <= 0 {
+		return fmt.Errorf("Invalid duration '%d', the next slots require a duration greater than 0", options.Duration)
+	}
+	if options.Next != nil && (options.Next.Count < 0 || options.Next.Count > maxNextCount) {
+		return fmt.Errorf("Invalid next count '%d', it must be between 0 and %d", options.Next.Count, maxNextCount)
+	}
+	return nil
+}
+
+// Número máximo de próximos slots a retornar
+const maxNextCount = 10
@@ -1,18 +1,32 @@
 package middlewares
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"regexp"
+	"time"
 
 	"Planner/api/constants"
 	"Planner/models"
+	"Planner/services"
 )
 
 // ValidateBodyMiddleware es un middleware que valida el cuerpo de la solicitud para cada Event
 func ValidatePlannerBodyMiddleware(next http.Handler) http.Handler {
+	return validatePlannerBody(next, 1)
+}
+
+// ValidatePlannerV2BodyMiddleware es un middleware que valida el cuerpo de la solicitud con intervalos semiabiertos
+func ValidatePlannerV2BodyMiddleware(next http.Handler) http.Handler {
+	return validatePlannerBody(next, models.HalfOpenVersion)
+}
+
+// validatePlannerBody valida el cuerpo de la solicitud según la versión de la ruta que la recibe
+func validatePlannerBody(next http.Handler, version int) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Verificar si el cuerpo de la solicitud está presente
 		if r.Body == nil {
@@ -20,56 +34,107 @@ func ValidatePlannerBodyMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// Decodificar el cuerpo de la solicitud en un Event
-		var request map[string][]models.Event
-		err := json.NewDecoder(r.Body).Decode(&request)
+		// Decodificar el cuerpo de la solicitud en un PlannerRequest
+		request, err := decodePlannerRequest(r.Body)
 		if err != nil {
 			http.Error(w, "Error while decoding JSON request body in middleware: "+err.Error(), http.StatusBadRequest)
 			return
 		}
+		request.Version = version
 
-		// Almacenar el cuerpo de la solicitud decodificado en el contexto
-		ctx := context.WithValue(r.Context(), constants.ContextKey{Key: "Planner"}, request)
+		// Los usuarios que solo declaran su disponibilidad se agregan a la solicitud sin eventos
+		for userID := range request.Available {
+			if _, ok := request.Users[userID]; !ok {
+				if request.Users == nil {
+					request.Users = make(map[string][]models.Event)
+				}
+				request.Users[userID] = []models.Event{}
+			}
+		}
 
-		for _, events := range request {
-			for _, event := range events {
+		// Validar las opciones de la solicitud
+		if err := validatePlannerOptions(request.Options, len(request.Users)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-				// Validar los campos del Event
-				if event.DayOfWeek == "" || event.StartTime == "" || event.EndTime == "" {
-					http.Error(w, "Missing required fields dayOfWeek, startTime or endTime in request body", http.StatusBadRequest)
-					return
-				}
+		// Validar la configuración del modo de recomendaciones
+		if request.Options.Rank != nil {
+			if err := validateRankOptions(*request.Options.Rank, version); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
 
-				// Validar el día de la semana
-				if !isValidDayOfWeek(event.DayOfWeek) {
-					http.Error(w, fmt.Sprintf("Invalid day of week '%s'", event.DayOfWeek), http.StatusBadRequest)
-					return
-				}
+		// Validar los grupos del formato de ventanas por grupo
+		if request.Options.Coalesce != nil {
+			if err := validateCoalesceOptions(request.Options, request.Users); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
 
-				// Validar el formato de tiempo
-				if !isValidTimeFormat(event.StartTime, event.EndTime) {
-					http.Error(w, fmt.Sprintf("Invalid time format for '%s' or '%s'", event.StartTime, event.EndTime), http.StatusBadRequest)
-					return
-				}
+		// Validar que los márgenes de tiempo sean de usuarios de la solicitud
+		for userID, buffer := range request.Options.UserBuffers {
+			if _, ok := request.Users[userID]; !ok {
+				http.Error(w, fmt.Sprintf("Unknown user '%s' in userBuffers", userID), http.StatusBadRequest)
+				return
+			}
+			if err := validateBuffer(buffer); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid buffer for '%s': %s", userID, err.Error()), http.StatusBadRequest)
+				return
+			}
+		}
 
-				// Verificar si la hora de inicio es anterior a la hora de finalización
-				if event.StartTime >= event.EndTime {
-					http.Error(w, fmt.Sprintf("Start time '%s' must be before end time '%s'", event.StartTime, event.EndTime), http.StatusBadRequest)
-					return
-				}
+		// Validar que los usuarios obligatorios estén en la solicitud
+		for _, userID := range request.Required {
+			if _, ok := request.Users[userID]; !ok {
+				http.Error(w, fmt.Sprintf("Unknown required attendee '%s'", userID), http.StatusBadRequest)
+				return
+			}
+		}
 
-				// Verificar el rango de tiempo
-				if !isValidTimeRange(event.StartTime, event.EndTime) {
-					http.Error(w, fmt.Sprintf("Invalid time range for '%s' or '%s'", event.StartTime, event.EndTime), http.StatusBadRequest)
-					return
-				}
+		// Almacenar el cuerpo de la solicitud decodificado en el contexto
+		ctx := context.WithValue(r.Context(), constants.ContextKey{Key: "Planner"}, request)
+
+		// Validar los eventos de cada usuario
+		for _, events := range request.Users {
+			if err := validateEvents(events, version); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 
-				// Verificar los minutos
-				if !isValidMinutes(event.StartTime, event.EndTime) {
-					http.Error(w, fmt.Sprintf("Invalid minutes for '%s' or '%s'", event.StartTime, event.EndTime), http.StatusBadRequest)
+			// Los eventos puntuales solo tienen sentido al planificar sobre un rango de fechas, mientras
+			// que en los eventos recurrentes la fecha es solo el inicio de la recurrencia
+			for _, event := range events {
+				if event.Date != "" && event.RRule == "" && request.Options.DateRange == nil {
+					http.Error(w, fmt.Sprintf("Event on date '%s' requires the dateRange option", event.Date), http.StatusBadRequest)
 					return
 				}
+			}
+		}
+
+		// Validar las ventanas de disponibilidad declaradas por cada usuario
+		for userID, windows := range request.Available {
+			if err := validateWindows(windows, version); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid available windows for '%s': %s", userID, err.Error()), http.StatusBadRequest)
+				return
+			}
+		}
 
+		// Validar las ventanas del horario laboral por defecto y de cada usuario
+		if err := validateWindows(request.WorkingHours.Default, version); err != nil {
+			http.Error(w, "Invalid default working hours: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		for userID, windows := range request.WorkingHours.Users {
+			if _, ok := request.Users[userID]; !ok {
+				http.Error(w, fmt.Sprintf("Unknown user '%s' in working hours", userID), http.StatusBadRequest)
+				return
+			}
+			if err := validateWindows(windows, version); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid working hours for '%s': %s", userID, err.Error()), http.StatusBadRequest)
+				return
 			}
 		}
 
@@ -78,8 +143,284 @@ func ValidatePlannerBodyMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// validateRouteOptions crea un middleware que valida con validate las opciones propias de una ruta, respondiendo
+// con un error 400 si no son válidas. Debe ejecutarse después de ValidatePlannerBodyMiddleware, que almacena la
+// solicitud en el contexto
+func validateRouteOptions(next http.Handler, validate func(models.PlannerRequest) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request, ok := r.Context().Value(constants.ContextKey{Key: "Planner"}).(models.PlannerRequest)
+		if !ok {
+			http.Error(w, "Error while decoding request body in middleware", http.StatusInternalServerError)
+			return
+		}
+
+		if err := validate(request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Campos del primer nivel del formato extendido de la solicitud
+var extendedRequestFields = []string{"users", "available", "required", "workingHours", "options"}
+
+// decodePlannerRequest decodifica el cuerpo de la solicitud, aceptando tanto el formato extendido
+// {"users": {...}, "options": {...}} como el formato original que solo contiene el mapa de usuarios
+func decodePlannerRequest(body io.Reader) (models.PlannerRequest, error) {
+	var request models.PlannerRequest
+
+	// Leer el cuerpo completo para poder decodificarlo según su formato
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return request, err
+	}
+
+	// Decodificar el primer nivel del cuerpo para identificar el formato
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return request, err
+	}
+
+	// En el formato extendido basta con cualquiera de sus campos, ya que "users" se puede omitir si todos los usuarios
+	// solo declaran su disponibilidad. En el formato original cada campo sería el arreglo de eventos de un usuario
+	for _, name := range extendedRequestFields {
+		if value, ok := fields[name]; ok && isExtendedRequestField(name, value) {
+			err = json.Unmarshal(data, &request)
+			return request, err
+		}
+	}
+
+	err = json.Unmarshal(data, &request.Users)
+	return request, err
+}
+
+// isExtendedRequestField verifica si un campo del primer nivel tiene el valor del formato extendido: "required" es
+// un arreglo de IDs de usuarios y los demás son objetos, mientras que un usuario tendría un arreglo de eventos
+func isExtendedRequestField(name string, value json.RawMessage) bool {
+	if name == "required" {
+		var usersId []string
+		return json.Unmarshal(value, &usersId) == nil && len(usersId) > 0
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(value), []byte("{"))
+}
+
+// validatePlannerOptions valida las opciones de búsqueda de la solicitud
+func validatePlannerOptions(options models.PlannerOptions, usersCount int) error {
+	// Con la semana circular una reunión puede abarcar varios días
+	maxDuration := 1440
+	if options.WeekWrap {
+		maxDuration = 7 * 1440
+	}
+	if options.Duration < 0 || options.Duration > maxDuration {
+		return fmt.Errorf("Invalid duration '%d', it must be between 0 and %d minutes", options.Duration, maxDuration)
+	}
+	if options.MinAttendees < 0 || options.MinAttendees > usersCount {
+		return fmt.Errorf("Invalid minAttendees '%d', it must be between 0 and the number of users (%d)", options.MinAttendees, usersCount)
+	}
+
+	// Validar la cuadrícula a la que se alinean los slots
+	switch options.Granularity {
+	case 0, 5, 10, 15, 30:
+	default:
+		return fmt.Errorf("Invalid granularity '%d', it must be 5, 10, 15 or 30 minutes", options.Granularity)
+	}
+
+	// Validar el criterio para ordenar los slots
+	switch options.SortBy {
+	case "", models.SortByTime, models.SortByAttendance, models.SortByDuration:
+	default:
+		return fmt.Errorf("Invalid sortBy '%s', it must be %s, %s or %s", options.SortBy, models.SortByTime, models.SortByAttendance, models.SortByDuration)
+	}
+
+	// Validar el tiempo límite del cálculo
+	if options.Timeout < 0 || options.Timeout > maxTimeoutMilliseconds {
+		return fmt.Errorf("Invalid timeout '%d', it must be between 0 and %d milliseconds", options.Timeout, maxTimeoutMilliseconds)
+	}
+
+	// Validar la resolución del mapa de calor, que debe dividir el día en intervalos iguales
+	if options.Heatmap != nil {
+		switch options.Heatmap.Resolution {
+		case 0, 5, 10, 15, 20, 30, 60:
+		default:
+			return fmt.Errorf("Invalid heatmap resolution '%d', it must be 5, 10, 15, 20, 30 or 60 minutes", options.Heatmap.Resolution)
+		}
+	}
+
+	// Validar el margen de tiempo por defecto alrededor de los bloques ocupados
+	if err := validateBuffer(options.Buffer); err != nil {
+		return fmt.Errorf("Invalid buffer: %s", err.Error())
+	}
+
+	// Validar el rango de fechas, si la solicitud planifica sobre fechas concretas
+	if options.DateRange != nil {
+		from, fromErr := time.Parse(services.DateLayout, options.DateRange.From)
+		to, toErr := time.Parse(services.DateLayout, options.DateRange.To)
+		if fromErr != nil || toErr != nil {
+			return fmt.Errorf("Invalid date range '%s' to '%s', dates must have the format YYYY-MM-DD", options.DateRange.From, options.DateRange.To)
+		}
+		if to.Before(from) || to.Sub(from) > maxDateRangeDays*24*time.Hour {
+			return fmt.Errorf("Invalid date range '%s' to '%s', it must span between 1 and %d days", options.DateRange.From, options.DateRange.To, maxDateRangeDays+1)
+		}
+	}
+
+	return nil
+}
+
+// validateBuffer valida que los minutos de margen antes y después de un bloque ocupado estén en el rango permitido
+func validateBuffer(buffer models.Buffer) error {
+	if buffer.Before < 0 || buffer.Before > maxBufferMinutes || buffer.After < 0 || buffer.After > maxBufferMinutes {
+		return fmt.Errorf("before '%d' and after '%d' must be between 0 and %d minutes", buffer.Before, buffer.After, maxBufferMinutes)
+	}
+	return nil
+}
+
+// validateCoalesceOptions valida que los grupos del formato de ventanas por grupo no estén vacíos y sean de usuarios
+// de la solicitud, y que el formato no se combine con el mapa de calor
+func validateCoalesceOptions(options models.PlannerOptions, users map[string][]models.Event) error {
+	if options.Heatmap != nil {
+		return fmt.Errorf("Invalid options, heatmap and coalesce can't be combined")
+	}
+	if len(options.Coalesce.Sets) > maxCoalesceSets {
+		return fmt.Errorf("Invalid coalesce sets, there can be at most %d sets", maxCoalesceSets)
+	}
+	for _, set := range options.Coalesce.Sets {
+		if len(set) == 0 {
+			return fmt.Errorf("Invalid coalesce sets, a set can't be empty")
+		}
+		for _, userID := range set {
+			if _, ok := users[userID]; !ok {
+				return fmt.Errorf("Unknown user '%s' in coalesce sets", userID)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRankOptions valida el número de slots, los pesos de cada criterio y las horas a evitar del modo de recomendaciones
+func validateRankOptions(rank models.RankOptions, version int) error {
+	if rank.Top < 0 || rank.Top > maxRankTop {
+		return fmt.Errorf("Invalid rank top '%d', it must be between 0 and %d", rank.Top, maxRankTop)
+	}
+	if rank.ProximityMinutes < 0 || rank.ProximityMinutes > 1440 {
+		return fmt.Errorf("Invalid rank proximityMinutes '%d', it must be between 0 and 1440", rank.ProximityMinutes)
+	}
+
+	for criterion, weight := range rank.Weights {
+		switch criterion {
+		case "attendance", "duration", "timeOfDay", "proximity":
+		default:
+			return fmt.Errorf("Unknown rank weight '%s'", criterion)
+		}
+		if weight < 0 {
+			return fmt.Errorf("Invalid rank weight '%s', it must not be negative", criterion)
+		}
+	}
+
+	for _, avoidTime := range rank.AvoidTimes {
+		if !isValidTimeFormat(avoidTime.StartTime, avoidTime.EndTime) || !isValidTimeRange(avoidTime.StartTime, avoidTime.EndTime, version) ||
+			!isValidMinutes(avoidTime.StartTime, avoidTime.EndTime) || avoidTime.StartTime == avoidTime.EndTime {
+			return fmt.Errorf("Invalid rank avoidTimes range '%s' to '%s'", avoidTime.StartTime, avoidTime.EndTime)
+		}
+	}
+
+	return nil
+}
+
+// validateEvents valida los campos de cada Event de una lista
+func validateEvents(events []models.Event, version int) error {
+	for _, event := range events {
+
+		// Validar los campos del Event, los eventos puntuales pueden omitir el día de la semana
+		if (event.DayOfWeek == "" && event.Date == "") || event.StartTime == "" || event.EndTime == "" {
+			return fmt.Errorf("Missing required fields dayOfWeek, startTime or endTime in request body")
+		}
+
+		// Validar el día de la semana
+		if event.DayOfWeek != "" && !isValidDayOfWeek(event.DayOfWeek) {
+			return fmt.Errorf("Invalid day of week '%s'", event.DayOfWeek)
+		}
+
+		// Validar la fecha de los eventos puntuales y que coincida con el día de la semana, si se envía
+		if event.Date != "" {
+			if _, err := time.Parse(services.DateLayout, event.Date); err != nil {
+				return fmt.Errorf("Invalid date '%s', it must have the format YYYY-MM-DD", event.Date)
+			}
+			if event.DayOfWeek != "" && event.DayOfWeek != services.GetDayOfWeekOfDate(event.Date) {
+				return fmt.Errorf("Day of week '%s' does not match date '%s'", event.DayOfWeek, event.Date)
+			}
+		}
+
+		// Solo se pueden cancelar eventos en una fecha específica
+		if event.Cancelled && (event.Date == "" || event.RRule != "") {
+			return fmt.Errorf("Cancelled events must have a date and no rrule")
+		}
+
+		// Validar el nivel de ocupación del evento
+		if event.Availability != "" && event.Availability != "busy" && event.Availability != "tentative" {
+			return fmt.Errorf("Invalid availability '%s', it must be busy or tentative", event.Availability)
+		}
+
+		// Las fechas excluidas solo aplican a eventos recurrentes
+		if len(event.ExDates) > 0 && event.RRule == "" {
+			return fmt.Errorf("Events with exdates must have an rrule")
+		}
+
+		// Validar el formato de tiempo
+		if !isValidTimeFormat(event.StartTime, event.EndTime) {
+			return fmt.Errorf("Invalid time format for '%s' or '%s'", event.StartTime, event.EndTime)
+		}
+
+		// Verificar que el evento tenga duración, si la hora de fin es anterior a la de inicio el evento cruza la medianoche
+		if event.StartTime == event.EndTime {
+			return fmt.Errorf("Start time '%s' must be different from end time '%s'", event.StartTime, event.EndTime)
+		}
+
+		// Verificar el rango de tiempo
+		if !isValidTimeRange(event.StartTime, event.EndTime, version) {
+			return fmt.Errorf("Invalid time range for '%s' or '%s'", event.StartTime, event.EndTime)
+		}
+
+		// Verificar los minutos
+		if !isValidMinutes(event.StartTime, event.EndTime) {
+			return fmt.Errorf("Invalid minutes for '%s' or '%s'", event.StartTime, event.EndTime)
+		}
+	}
+
+	return nil
+}
+
+// validateWindows valida las ventanas de un horario laboral o de disponibilidad, que son semanales y por lo tanto no tienen fecha
+func validateWindows(windows []models.Event, version int) error {
+	for _, window := range windows {
+		if window.Date != "" || window.Cancelled || window.RRule != "" {
+			return fmt.Errorf("Windows can't have a date")
+		}
+		if window.Availability != "" {
+			return fmt.Errorf("Windows can't have an availability")
+		}
+	}
+	return validateEvents(windows, version)
+}
+
 // Funciones de ayuda para validaciones específicas
 
+// Número máximo de días después de la fecha inicial de un rango de fechas
+const maxDateRangeDays = 30
+
+// Número máximo de slots del modo de recomendaciones
+const maxRankTop = 100
+
+// Número máximo de minutos de margen antes o después de un bloque ocupado
+const maxBufferMinutes = 240
+
+// Número máximo de grupos del formato de ventanas por grupo
+const maxCoalesceSets = 50
+
+// Tiempo límite máximo que una solicitud puede pedir para el cálculo, en milisegundos
+const maxTimeoutMilliseconds = 60000
+
 func isValidDayOfWeek(dayOfWeek string) bool {
 	return regexp.MustCompile(`^[lmijvsd]$`).MatchString(dayOfWeek)
 }
@@ -88,7 +429,11 @@ func isValidTimeFormat(startTime, endTime string) bool {
 	return regexp.MustCompile(`^\d{4}$`).MatchString(startTime) && regexp.MustCompile(`^\d{4}$`).MatchString(endTime)
 }
 
-func isValidTimeRange(startTime, endTime string) bool {
+func isValidTimeRange(startTime, endTime string, version int) bool {
+	// Con intervalos semiabiertos un evento puede terminar a las "2400"
+	if version >= models.HalfOpenVersion && endTime == "2400" {
+		return startTime >= "0000" && startTime < "2400"
+	}
 	return (startTime >= "0000" && startTime < "2400" && endTime >= "0000" && endTime < "2400")
 }
 
@@ -0,0 +1,62 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"Planner/api/constants"
+	"Planner/models"
+)
+
+func TestDecodePlannerRequest(t *testing.T) {
+	// Caso de prueba: el formato original, con un usuario cuyo ID coincide con un campo del formato extendido
+	request, err := decodePlannerRequest(strings.NewReader(`{"options": [{"dayOfWeek": "l", "startTime": "0800", "endTime": "0900"}]}`))
+	expectedUsers := map[string][]models.Event{"options": {{DayOfWeek: "l", StartTime: "0800", EndTime: "0900"}}}
+	if err != nil || !reflect.DeepEqual(request.Users, expectedUsers) {
+		t.Errorf("ERROR Expected: %v, Got: %v (%v)", expectedUsers, request.Users, err)
+	}
+
+	// Caso de prueba: el formato extendido sin "users", detectado por cualquiera de sus otros campos
+	for _, body := range []string{
+		`{"available": {"1": []}}`,
+		`{"required": ["1"]}`,
+		`{"workingHours": {"default": []}}`,
+		`{"options": {"duration": 30}}`,
+	} {
+		request, err := decodePlannerRequest(strings.NewReader(body))
+		if err != nil || request.Users != nil {
+			t.Errorf("ERROR Expected the extended format for '%s', Got: %v (%v)", body, request.Users, err)
+		}
+	}
+}
+
+func TestValidatePlannerBodyWithOnlyAvailableUsers(t *testing.T) {
+	// Caso de prueba: una solicitud en la que todos los usuarios solo declaran su disponibilidad
+	body := `{
+		"available": {
+			"1": [{"dayOfWeek": "l", "startTime": "0900", "endTime": "1159"}],
+			"2": [{"dayOfWeek": "l", "startTime": "1000", "endTime": "1259"}]
+		},
+		"options": {"duration": 60}
+	}`
+
+	var actualRequest models.PlannerRequest
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actualRequest = r.Context().Value(constants.ContextKey{Key: "Planner"}).(models.PlannerRequest)
+	})
+	recorder := httptest.NewRecorder()
+	ValidatePlannerBodyMiddleware(next).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/planner", strings.NewReader(body)))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("ERROR Expected: %d, Got: %d (%s)", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+
+	// Los usuarios se agregan a la solicitud sin eventos
+	expectedUsers := map[string][]models.Event{"1": {}, "2": {}}
+	if !reflect.DeepEqual(actualRequest.Users, expectedUsers) || actualRequest.Options.Duration != 60 {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedUsers, actualRequest.Users)
+	}
+}
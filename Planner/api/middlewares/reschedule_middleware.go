@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"Planner/models"
+)
+
+// ValidateRescheduleMiddleware es un middleware que valida la duración de la reunión, el número de movimientos y
+// los eventos movibles. Debe ejecutarse después de ValidatePlannerBodyMiddleware, que almacena la solicitud en el contexto
+func ValidateRescheduleMiddleware(next http.Handler) http.Handler {
+	return validateRouteOptions(next, func(request models.PlannerRequest) error {
+		return validateRescheduleRequest(request)
+	})
+}
+
+// validateRescheduleRequest valida que la reunión tenga duración, el número máximo de movimientos y que los eventos
+// movibles correspondan a una sola ocurrencia: semanales en la semana genérica y puntuales en un rango de fechas
+func validateRescheduleRequest(request models.PlannerRequest) error {
+	if request.Options.Duration <= 0 {
+		return fmt.Errorf("Invalid duration '%d', rescheduling requires a duration greater than 0", request.Options.Duration)
+	}
+	if reschedule := request.Options.Reschedule; reschedule != nil && (reschedule.MaxMoves < 0 || reschedule.MaxMoves > maxRescheduleMoves) {
+		return fmt.Errorf("Invalid reschedule maxMoves '%d', it must be between 0 and %d", reschedule.MaxMoves, maxRescheduleMoves)
+	}
+
+	movableCount := 0
+	for _, events := range request.Users {
+		for _, event := range events {
+			if !event.Movable {
+				continue
+			}
+			movableCount++
+			if event.RRule != "" || event.Cancelled {
+				return fmt.Errorf("Movable events can't have an rrule or be cancelled")
+			}
+			if request.Options.DateRange != nil && event.Date == "" {
+				return fmt.Errorf("Movable events require a date with the dateRange option")
+			}
+		}
+	}
+	if movableCount > maxMovableEvents {
+		return fmt.Errorf("Too many movable events '%d', there can be at most %d", movableCount, maxMovableEvents)
+	}
+	return nil
+}
+
+// Número máximo de eventos a mover y de eventos movibles de una solicitud
+const maxRescheduleMoves = 3
+const maxMovableEvents = 20
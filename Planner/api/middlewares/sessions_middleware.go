@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"Planner/models"
+)
+
+// ValidateSessionsMiddleware es un middleware que valida las opciones de las sesiones recurrentes. Debe ejecutarse
+// después de ValidatePlannerBodyMiddleware, que almacena la solicitud en el contexto
+func ValidateSessionsMiddleware(next http.Handler) http.Handler {
+	return validateRouteOptions(next, func(request models.PlannerRequest) error {
+		return validateSessionOptions(request.Options)
+	})
+}
+
+// validateSessionOptions valida el número, la duración y la separación de las sesiones, que se planifican sobre
+// la semana genérica
+func validateSessionOptions(options models.PlannerOptions) error {
+	if options.Sessions == nil {
+		return fmt.Errorf("Missing required option sessions in request body")
+	}
+	if options.Duration <= 0 {
+		return fmt.Errorf("Invalid duration '%d', sessions require a duration greater than 0", options.Duration)
+	}
+	if options.Sessions.Count < 1 || options.Sessions.Count > 7 {
+		return fmt.Errorf("Invalid sessions count '%d', it must be between 1 and 7", options.Sessions.Count)
+	}
+	if options.Sessions.MinDaysApart < 0 || options.Sessions.MinDaysApart > 3 {
+		return fmt.Errorf("Invalid sessions minDaysApart '%d', it must be between 0 and 3", options.Sessions.MinDaysApart)
+	}
+	if options.DateRange != nil {
+		return fmt.Errorf("Sessions are planned over a generic week and don't support the dateRange option")
+	}
+	if options.WeekWrap {
+		return fmt.Errorf("Sessions are planned within each day and don't support the weekWrap option")
+	}
+	return nil
+}
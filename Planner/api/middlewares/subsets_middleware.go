@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"Planner/models"
+)
+
+// ValidateSubsetsMiddleware es un middleware que valida las opciones de los subconjuntos de asistentes. Debe
+// ejecutarse después de ValidatePlannerBodyMiddleware, que almacena la solicitud en el contexto
+func ValidateSubsetsMiddleware(next http.Handler) http.Handler {
+	return validateRouteOptions(next, func(request models.PlannerRequest) error {
+		return validateSubsetOptions(request.Options, len(request.Users))
+	})
+}
+
+// validateSubsetOptions valida el tamaño de los subconjuntos, cuántos retornar y la duración de la reunión
+func validateSubsetOptions(options models.PlannerOptions, usersCount int) error {
+	if options.Subsets == nil {
+		return fmt.Errorf("Missing required option subsets in request body")
+	}
+	if options.Duration <= 0 {
+		return fmt.Errorf("Invalid duration '%d', subsets require a duration greater than 0", options.Duration)
+	}
+	if options.Subsets.Size < 1 || options.Subsets.Size > usersCount {
+		return fmt.Errorf("Invalid subsets size '%d', it must be between 1 and the number of users (%d)", options.Subsets.Size, usersCount)
+	}
+	if options.Subsets.Top < 0 || options.Subsets.Top > maxSubsetsTop {
+		return fmt.Errorf("Invalid subsets top '%d', it must be between 0 and %d", options.Subsets.Top, maxSubsetsTop)
+	}
+	return nil
+}
+
+// Número máximo de subconjuntos a retornar
+const maxSubsetsTop = 20
@@ -19,6 +19,13 @@ func main() {
 	// Configurar los manejadores de las rutas
 	router.HandleFunc("/hello", handlers.HelloHandler).Methods("GET")
 	router.Handle("/planner", middlewares.ValidatePlannerBodyMiddleware(http.HandlerFunc(handlers.PlannerHandler))).Methods("POST")
+	router.Handle("/planner/sessions", middlewares.ValidatePlannerBodyMiddleware(middlewares.ValidateSessionsMiddleware(http.HandlerFunc(handlers.SessionsHandler)))).Methods("POST")
+	router.Handle("/planner/subsets", middlewares.ValidatePlannerBodyMiddleware(middlewares.ValidateSubsetsMiddleware(http.HandlerFunc(handlers.SubsetsHandler)))).Methods("POST")
+	router.Handle("/planner/free", middlewares.ValidatePlannerBodyMiddleware(middlewares.ValidateFreeMiddleware(http.HandlerFunc(handlers.FreeHandler)))).Methods("POST")
+	router.Handle("/planner/next", middlewares.ValidatePlannerBodyMiddleware(middlewares.ValidateNextMiddleware(http.HandlerFunc(handlers.NextHandler)))).Methods("POST")
+	router.Handle("/planner/check", middlewares.ValidatePlannerBodyMiddleware(middlewares.ValidateCheckMiddleware(http.HandlerFunc(handlers.CheckHandler)))).Methods("POST")
+	router.Handle("/planner/reschedule", middlewares.ValidatePlannerBodyMiddleware(middlewares.ValidateRescheduleMiddleware(http.HandlerFunc(handlers.RescheduleHandler)))).Methods("POST")
+	router.Handle("/v2/planner", middlewares.ValidatePlannerV2BodyMiddleware(http.HandlerFunc(handlers.PlannerHandler))).Methods("POST")
 
 	// Leer el puerto del entorno
 	port := os.Getenv("PORT")
@@ -2,4 +2,4 @@ module Planner
 
 go 1.22.2
 
-require github.com/gorilla/mux v1.8.1 // indirect
+require github.com/gorilla/mux v1.8.1
@@ -0,0 +1,8 @@
+package models
+
+// AttendeeWindows representa un grupo de usuarios y las ventanas continuas más largas en las que todos están libres
+type AttendeeWindows struct {
+	Attendees []string       `json:"attendees"`
+	Size      int            `json:"size"`
+	Windows   []PlannerEvent `json:"windows"` // Ventanas maximales del grupo, del lunes al domingo (o por fecha)
+}
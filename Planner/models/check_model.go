@@ -0,0 +1,19 @@
+package models
+
+// ProposalCheck representa el resultado de verificar una reunión propuesta contra los horarios de los usuarios
+type ProposalCheck struct {
+	Available      bool        `json:"available"` // Veredicto: asisten todos los obligatorios y al menos minAttendees usuarios
+	UsersAvailable int         `json:"usersAvailable"`
+	Duration       int         `json:"duration"` // Duración de la reunión en minutos
+	Users          []UserCheck `json:"users"`
+}
+
+// UserCheck representa si un usuario puede asistir a la reunión propuesta y los conflictos que se lo impiden
+type UserCheck struct {
+	UserID         string        `json:"userId"`
+	Available      bool          `json:"available"`
+	Required       bool          `json:"required,omitempty"`
+	Tentative      bool          `json:"tentative,omitempty"` // Ocupado solo por bloques tentativos
+	OverlapMinutes int           `json:"overlapMinutes"`      // Minutos de la reunión en los que el usuario está ocupado
+	Conflicts      []BlockSource `json:"conflicts"`           // Orígenes de los bloques ocupados que se solapan con la reunión
+}
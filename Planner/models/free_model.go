@@ -0,0 +1,16 @@
+package models
+
+// UserStatus representa si un usuario está libre en el momento consultado y hasta cuándo, o desde cuándo lo estará
+type UserStatus struct {
+	UserID    string  `json:"userId"`
+	Tentative bool    `json:"tentative,omitempty"` // Ocupado solo por bloques tentativos hasta FreeFrom
+	FreeUntil *Moment `json:"freeUntil,omitempty"` // Último minuto libre antes del siguiente bloque ocupado
+	FreeFrom  *Moment `json:"freeFrom,omitempty"`  // Primer minuto libre después del bloque ocupado actual
+}
+
+// Moment representa un minuto de un día de la semana o de una fecha
+type Moment struct {
+	DayOfWeek string `json:"dayOfWeek"`
+	Date      string `json:"date,omitempty"` // Presente solo cuando la solicitud planifica sobre un rango de fechas
+	Time      string `json:"time"`           // Hora en formato "HH:MM"
+}
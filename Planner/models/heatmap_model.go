@@ -0,0 +1,10 @@
+package models
+
+// HeatmapDay representa la disponibilidad de un día (o fecha) dividida en intervalos de la misma duración
+type HeatmapDay struct {
+	DayOfWeek  string     `json:"dayOfWeek"`
+	Date       string     `json:"date,omitempty"` // Presente solo cuando la solicitud planifica sobre un rango de fechas
+	Resolution int        `json:"resolution"`     // Minutos de cada intervalo, el primero inicia a las 00:00
+	Counts     []int      `json:"counts"`         // Número de usuarios libres durante todo cada intervalo
+	Attendees  [][]string `json:"attendees,omitempty"`
+}
@@ -0,0 +1,154 @@
+package models
+
+// Versión de la solicitud y la respuesta con intervalos semiabiertos [inicio, fin) de la ruta /v2/planner: la hora
+// de fin no forma parte del intervalo y puede ser "2400". La ruta /planner usa intervalos cerrados, donde la hora de
+// fin es el último minuto ocupado o libre
+const HalfOpenVersion = 2
+
+// Criterios para ordenar los slots de la respuesta
+const (
+	SortByTime       = "time"       // Del lunes al domingo (o por fecha) y luego por hora de inicio
+	SortByAttendance = "attendance" // Primero los slots con más asistentes
+	SortByDuration   = "duration"   // Primero los slots más largos
+)
+
+// PlannerRequest representa el cuerpo de la solicitud a la ruta /planner
+type PlannerRequest struct {
+	Version      int                `json:"-"` // Versión de la ruta que recibió la solicitud
+	Users        map[string][]Event `json:"users"`
+	Available    map[string][]Event `json:"available"` // Ventanas semanales en las que cada usuario declara estar disponible
+	Required     []string           `json:"required"`  // IDs de los usuarios obligatorios, el resto son opcionales
+	WorkingHours WorkingHours       `json:"workingHours"`
+	Options      PlannerOptions     `json:"options"`
+}
+
+// WorkingHours representa las ventanas en las que los usuarios están dispuestos a reunirse
+type WorkingHours struct {
+	Default []Event            `json:"default"` // Ventanas de los usuarios sin un horario propio
+	Users   map[string][]Event `json:"users"`   // Ventanas propias de cada usuario
+}
+
+// PlannerOptions representa las opciones de búsqueda de la solicitud
+type PlannerOptions struct {
+	Duration     int  `json:"duration"`     // Duración mínima de la reunión en minutos (0 para no filtrar)
+	MinAttendees int  `json:"minAttendees"` // Número mínimo de asistentes (0 para exigir a todos los usuarios, o a los obligatorios si los hay)
+	WeekWrap     bool `json:"weekWrap"`     // Tratar la semana como una línea de tiempo circular, uniendo los slots entre días
+	Granularity  int  `json:"granularity"`  // Alinear los slots a una cuadrícula de 5, 10, 15 o 30 minutos (0 para no alinear)
+	Timeout      int  `json:"timeout"`      // Tiempo límite del cálculo en milisegundos (0 para el tiempo por defecto del servidor)
+
+	// Criterio para ordenar los slots: "time", "attendance" o "duration". Vacío para ordenar por hora, o por
+	// asistentes opcionales si hay usuarios obligatorios
+	SortBy string `json:"sortBy"`
+
+	// Rango de fechas a planificar. Si está presente, los slots se calculan para cada fecha del rango
+	// aplicando el horario semanal más los eventos puntuales, en lugar de para una semana genérica
+	DateRange *DateRange `json:"dateRange"`
+
+	// Margen de tiempo alrededor de cada bloque ocupado, por defecto y de cada usuario
+	Buffer      Buffer            `json:"buffer"`
+	UserBuffers map[string]Buffer `json:"userBuffers"`
+
+	// Modo de recomendaciones: si está presente, los slots se ordenan por puntaje y solo se retornan los mejores
+	Rank *RankOptions `json:"rank"`
+
+	// Modo de explicación: si está activo, cada slot indica los usuarios que no asisten y los eventos que se lo impiden
+	Explain bool `json:"explain"`
+
+	// Formato de mapa de calor: si está presente, la respuesta es la cantidad de usuarios libres en intervalos fijos de cada día
+	Heatmap *HeatmapOptions `json:"heatmap"`
+
+	// Formato de ventanas por grupo: si está presente, la respuesta son las ventanas maximales de cada grupo de asistentes
+	Coalesce *CoalesceOptions `json:"coalesce"`
+
+	// Momento de la consulta de la ruta /planner/free
+	At *AtOptions `json:"at"`
+
+	// Reunión propuesta de la ruta /planner/check
+	Proposal *ProposalOptions `json:"proposal"`
+
+	// Número máximo de eventos a mover de la ruta /planner/reschedule
+	Reschedule *RescheduleOptions `json:"reschedule"`
+
+	// Configuración de los próximos slots de la ruta /planner/next
+	Next *NextOptions `json:"next"`
+
+	// Configuración de las sesiones recurrentes de la ruta /planner/sessions
+	Sessions *SessionOptions `json:"sessions"`
+
+	// Configuración de los subconjuntos de asistentes de la ruta /planner/subsets
+	Subsets *SubsetOptions `json:"subsets"`
+}
+
+// SubsetOptions representa el tamaño mínimo de los subconjuntos de asistentes y cuántos retornar
+type SubsetOptions struct {
+	Size int `json:"size"` // Número mínimo de asistentes de cada subconjunto
+	Top  int `json:"top"`  // Número de subconjuntos a retornar (0 para 5)
+}
+
+// AtOptions representa el día (o fecha) y la hora en la que se consulta quién está libre
+type AtOptions struct {
+	DayOfWeek string `json:"dayOfWeek"`
+	Date      string `json:"date"` // Fecha del rango de fechas, en lugar del día de la semana
+	Time      string `json:"time"` // Hora en formato "hhmm"
+}
+
+// ProposalOptions representa el día (o fecha) y las horas de una reunión propuesta, con el formato de un evento
+type ProposalOptions struct {
+	DayOfWeek string `json:"dayOfWeek"`
+	Date      string `json:"date"`      // Fecha del rango de fechas, en lugar del día de la semana
+	StartTime string `json:"startTime"` // Hora de inicio en formato "hhmm"
+	EndTime   string `json:"endTime"`   // Último minuto de la reunión en formato "hhmm", anterior al inicio si cruza la medianoche
+}
+
+// RescheduleOptions representa cuántos eventos se pueden mover como máximo para liberar un slot
+type RescheduleOptions struct {
+	MaxMoves int `json:"maxMoves"` // Número máximo de eventos a mover (0 para 2)
+}
+
+// NextOptions representa cuántos de los próximos slots retornar
+type NextOptions struct {
+	Count int `json:"count"` // Número de slots a retornar (0 para 1)
+}
+
+// CoalesceOptions representa los grupos de asistentes cuyas ventanas se quieren conocer
+type CoalesceOptions struct {
+	Sets [][]string `json:"sets"` // IDs de cada grupo (vacío para todos los grupos que pueden reunirse)
+}
+
+// HeatmapOptions representa la resolución del mapa de calor y si incluye los usuarios libres en cada intervalo
+type HeatmapOptions struct {
+	Resolution       int  `json:"resolution"`       // Minutos de cada intervalo (0 para 15 minutos)
+	IncludeAttendees bool `json:"includeAttendees"` // Incluir los IDs de los usuarios libres en cada intervalo
+}
+
+// SessionOptions representa el número de sesiones semanales de un grupo y la separación mínima entre ellas
+type SessionOptions struct {
+	Count        int `json:"count"`        // Número de sesiones por semana, cada una en un día distinto
+	MinDaysApart int `json:"minDaysApart"` // Días mínimos entre el inicio de dos sesiones (0 para 1, días distintos)
+}
+
+// RankOptions representa la configuración del puntaje de los slots recomendados
+type RankOptions struct {
+	Top              int                `json:"top"`              // Número de slots a retornar (0 para los 10 mejores)
+	Weights          map[string]float64 `json:"weights"`          // Pesos que reemplazan a los pesos por defecto de cada criterio
+	AvoidTimes       []TimeRange        `json:"avoidTimes"`       // Horas a evitar cada día (por defecto antes de las 08:00 y de 12:00 a 14:00)
+	ProximityMinutes int                `json:"proximityMinutes"` // Distancia máxima a un evento para considerarlo cercano (0 para 60 minutos)
+}
+
+// TimeRange representa un rango de horas "HHMM" que se repite todos los días
+type TimeRange struct {
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+}
+
+// Buffer representa los minutos de margen antes y después de un bloque ocupado (por ejemplo, para desplazarse)
+type Buffer struct {
+	Before int `json:"before"`
+	After  int `json:"after"`
+}
+
+// DateRange representa un rango de fechas "AAAA-MM-DD", incluyendo ambos extremos
+type DateRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// Reschedule representa los eventos a mover para liberar un slot en el que todos los usuarios pueden reunirse
+type Reschedule struct {
+	Moves []EventMove   `json:"moves"` // Movimientos sugeridos, vacío si el slot ya está libre o si no hay solución
+	Slot  *PlannerEvent `json:"slot"`  // Reunión que queda libre después de los movimientos, nil si no hay solución
+}
+
+// EventMove representa el cambio de un evento de un usuario a otro momento en el que está libre
+type EventMove struct {
+	UserID string `json:"userId"`
+	From   Event  `json:"from"` // Evento original de la solicitud
+	To     Event  `json:"to"`   // El mismo evento en su nuevo día (o fecha) y horas
+}
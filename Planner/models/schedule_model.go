@@ -5,22 +5,102 @@ type Event struct {
 	DayOfWeek string `json:"dayOfWeek"`
 	StartTime string `json:"startTime"`
 	EndTime   string `json:"endTime"`
+	Date      string `json:"date,omitempty"`      // Fecha "AAAA-MM-DD" de un evento puntual, vacío para eventos semanales
+	Cancelled bool   `json:"cancelled,omitempty"` // Indica que el horario semanal queda libre en la fecha del evento
+
+	// Nivel de ocupación del evento: "busy" (por defecto) o "tentative" para los bloques que el usuario puede liberar
+	// si es necesario, como el gimnasio o el almuerzo
+	Availability string `json:"availability,omitempty"`
+
+	// Indica que el usuario puede mover el evento a otro momento libre, como una sesión de estudio. Solo lo usa la
+	// ruta /planner/reschedule
+	Movable bool `json:"movable,omitempty"`
+
+	// Regla de recurrencia semanal de iCalendar y fechas excluidas. En un evento recurrente, Date es la fecha
+	// de la primera ocurrencia posible (DTSTART)
+	RRule   string   `json:"rrule,omitempty"`
+	ExDates []string `json:"exdates,omitempty"`
+
+	// Evento recurrente de la solicitud del que proviene una ocurrencia expandida, nil en los demás eventos. Es el
+	// origen que se reporta en los conflictos, ya que la ocurrencia no tiene la regla ni las fechas excluidas
+	RecurringEvent *Event `json:"-"`
 }
 
 // PlannerEvent representa un evento en el horario con información adicional
 type PlannerEvent struct {
-	DayOfWeek      string   `json:"dayOfWeek"`
-	StartTime      string   `json:"startTime"`
-	EndTime        string   `json:"endTime"`
-	UsersAvailable int      `json:"usersAvailable"`
-	Attendees      []string `json:"attendees"`
-	Duration       int      `json:"duration"`
+	DayOfWeek       string   `json:"dayOfWeek"`
+	StartTime       string   `json:"startTime"`
+	EndTime         string   `json:"endTime"`
+	UsersAvailable  int      `json:"usersAvailable"`
+	Attendees       []string `json:"attendees"`
+	Duration        int      `json:"duration"`
+	LatestStartTime string   `json:"latestStartTime,omitempty"`
+
+	// Campos presentes solo cuando el slot o su último inicio posible caen en otro día (opción weekWrap)
+	EndDayOfWeek         string `json:"endDayOfWeek,omitempty"`
+	LatestStartDayOfWeek string `json:"latestStartDayOfWeek,omitempty"`
+
+	// Campos presentes solo cuando la solicitud planifica sobre un rango de fechas
+	Date            string `json:"date,omitempty"`
+	EndDate         string `json:"endDate,omitempty"`
+	LatestStartDate string `json:"latestStartDate,omitempty"`
+
+	// Campos presentes solo cuando la solicitud distingue asistentes obligatorios y opcionales
+	RequiredAttendees []string `json:"requiredAttendees,omitempty"`
+	OptionalAttendees []string `json:"optionalAttendees,omitempty"`
+
+	// Usuarios que no están libres por completo pero cuyos únicos eventos durante el slot son tentativos
+	TentativeAttendees []string `json:"tentativeAttendees,omitempty"`
+
+	// Campo presente solo en el modo de recomendaciones ordenadas por puntaje (opción rank)
+	Score *SlotScore `json:"score,omitempty"`
+
+	// Campo presente solo en el modo de explicación (opción explain): los usuarios que no asisten y qué se lo impide
+	Absentees []Absentee `json:"absentees,omitempty"`
+}
+
+// Absentee representa a un usuario que no está libre durante todo un slot y los orígenes de sus bloques ocupados
+// que se solapan con el slot
+type Absentee struct {
+	UserID    string        `json:"userId"`
+	Conflicts []BlockSource `json:"conflicts"`
+}
+
+// SlotScore representa el puntaje total de un slot y el puntaje entre 0 y 1 de cada criterio
+type SlotScore struct {
+	Total      float64 `json:"total"`
+	Attendance float64 `json:"attendance"` // Proporción de los usuarios que pueden asistir
+	Duration   float64 `json:"duration"`   // Duración respecto al slot más largo
+	TimeOfDay  float64 `json:"timeOfDay"`  // Proporción de la reunión que cabe fuera de las horas a evitar
+	Proximity  float64 `json:"proximity"`  // Proporción de los asistentes con un evento cerca del slot
 }
 
 // TimeBlock representa un bloque de tiempo con un inicio y fin en minutos
 type TimeBlock struct {
-	StartMinute int `json:"startMinute"`
-	EndMinute   int `json:"endMinute"`
+	StartMinute int  `json:"startMinute"`
+	EndMinute   int  `json:"endMinute"`
+	Tentative   bool `json:"tentative,omitempty"` // Indica que el usuario puede liberar el bloque si es necesario
+
+	// Indica que el bloque es el tiempo fuera del horario laboral o de la disponibilidad del usuario y no un evento,
+	// por lo que no recibe margen. Solo se distingue antes de normalizar el horario
+	Outside bool `json:"outside,omitempty"`
+
+	// Orígenes del bloque, que se conservan al dividirlo y al fusionarlo con otros. Solo se registran en el modo
+	// de explicación (opción explain)
+	Sources []*BlockSource `json:"sources,omitempty"`
+}
+
+// Motivos por los que un usuario tiene un bloque ocupado
+const (
+	ReasonEvent               = "event"               // Un evento del usuario
+	ReasonOutsideWorkingHours = "outsideWorkingHours" // El tiempo fuera de su horario laboral
+	ReasonOutsideAvailability = "outsideAvailability" // El tiempo fuera de las ventanas en las que declara estar disponible
+)
+
+// BlockSource representa el origen de un bloque ocupado
+type BlockSource struct {
+	Reason string `json:"reason"`
+	Event  *Event `json:"event,omitempty"` // Evento original, presente solo cuando el motivo es un evento
 }
 
 // UserTimeBlock representa un bloque de tiempo con un inicio y fin en minutos y un ID de usuario
@@ -31,7 +111,13 @@ type UserTimeBlock struct {
 
 // ScheduleModel representa el horario de una persona
 type ScheduleModel struct {
-	ID        string      `json:"id"`
+	ID       string `json:"id"`
+	Required bool   `json:"required"`
+
+	// Minutos de margen antes y después de cada TimeBlock ocupado, aplicados al normalizar el horario
+	BufferBefore int `json:"bufferBefore"`
+	BufferAfter  int `json:"bufferAfter"`
+
 	Monday    []TimeBlock `json:"l"`
 	Tuesday   []TimeBlock `json:"m"`
 	Wednesday []TimeBlock `json:"i"`
@@ -39,4 +125,7 @@ type ScheduleModel struct {
 	Friday    []TimeBlock `json:"v"`
 	Saturday  []TimeBlock `json:"s"`
 	Sunday    []TimeBlock `json:"d"`
+
+	// TimeBlocks de cada fecha "AAAA-MM-DD" cuando la solicitud planifica sobre un rango de fechas
+	Dates map[string][]TimeBlock `json:"dates,omitempty"`
 }
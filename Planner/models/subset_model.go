@@ -0,0 +1,8 @@
+package models
+
+// AttendeeSubset representa un grupo de usuarios que pueden reunirse juntos y los slots en los que pueden hacerlo
+type AttendeeSubset struct {
+	Attendees []string       `json:"attendees"`
+	Size      int            `json:"size"`
+	Slots     []PlannerEvent `json:"slots"` // Slots donde todo el grupo está libre, en el orden de /planner
+}
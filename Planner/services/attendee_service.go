@@ -0,0 +1,69 @@
+package services
+
+import (
+	"sort"
+
+	"Planner/models"
+)
+
+// Función para obtener los IDs ordenados de los usuarios obligatorios
+func getRequiredUsers(schedules []models.ScheduleModel) []string {
+	var requiredUsers []string
+	for _, schedule := range schedules {
+		if schedule.Required {
+			requiredUsers = append(requiredUsers, schedule.ID)
+		}
+	}
+	sort.Strings(requiredUsers)
+	return requiredUsers
+}
+
+// Función para descartar los slots donde falta algún usuario obligatorio, separar los asistentes
+// obligatorios de los opcionales y ordenar los slots según la cantidad de asistentes opcionales
+func applyAttendeeRoles(plannerEvents []models.PlannerEvent, requiredUsers []string) []models.PlannerEvent {
+	isRequired := make(map[string]bool, len(requiredUsers))
+	for _, userID := range requiredUsers {
+		isRequired[userID] = true
+	}
+
+	rankedEvents := make([]models.PlannerEvent, 0, len(plannerEvents))
+	for _, event := range plannerEvents {
+		// Descartar el slot si algún usuario obligatorio está ocupado, es decir, si no todos los usuarios de la
+		// tabla de obligatorios están entre sus asistentes
+		if newUserSetOf(requiredUsers, event.Attendees).count() != len(requiredUsers) {
+			continue
+		}
+
+		rankedEvents = append(rankedEvents, splitAttendeeRoles(event, isRequired))
+	}
+
+	// Ordenar los slots de mayor a menor cantidad de asistentes opcionales, y luego por fecha, día y hora de inicio
+	sort.SliceStable(rankedEvents, func(i, j int) bool {
+		if len(rankedEvents[i].OptionalAttendees) != len(rankedEvents[j].OptionalAttendees) {
+			return len(rankedEvents[i].OptionalAttendees) > len(rankedEvents[j].OptionalAttendees)
+		}
+		if rankedEvents[i].Date != rankedEvents[j].Date {
+			return rankedEvents[i].Date < rankedEvents[j].Date
+		}
+		if rankedEvents[i].DayOfWeek != rankedEvents[j].DayOfWeek {
+			return dayIndex(rankedEvents[i].DayOfWeek) < dayIndex(rankedEvents[j].DayOfWeek)
+		}
+		return rankedEvents[i].StartTime < rankedEvents[j].StartTime
+	})
+
+	return rankedEvents
+}
+
+// Función auxiliar para separar los asistentes obligatorios de un slot de los opcionales
+func splitAttendeeRoles(event models.PlannerEvent, isRequired map[string]bool) models.PlannerEvent {
+	event.RequiredAttendees = []string{}
+	event.OptionalAttendees = []string{}
+	for _, userID := range event.Attendees {
+		if isRequired[userID] {
+			event.RequiredAttendees = append(event.RequiredAttendees, userID)
+		} else {
+			event.OptionalAttendees = append(event.OptionalAttendees, userID)
+		}
+	}
+	return event
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestApplyAttendeeRoles(t *testing.T) {
+	// Caso de prueba: se descartan los slots sin el usuario obligatorio y se ordenan por opcionales
+	plannerEvents := []models.PlannerEvent{
+		{DayOfWeek: "m", StartTime: "08:00", EndTime: "09:59", UsersAvailable: 2, Attendees: []string{"a", "prof"}, Duration: 120},
+		{DayOfWeek: "l", StartTime: "10:00", EndTime: "11:59", UsersAvailable: 2, Attendees: []string{"a", "b"}, Duration: 120},
+		{DayOfWeek: "l", StartTime: "12:00", EndTime: "13:59", UsersAvailable: 3, Attendees: []string{"a", "b", "prof"}, Duration: 120},
+		{DayOfWeek: "l", StartTime: "08:00", EndTime: "09:59", UsersAvailable: 1, Attendees: []string{"prof"}, Duration: 120},
+		{DayOfWeek: "l", StartTime: "06:00", EndTime: "07:59", UsersAvailable: 2, Attendees: []string{"b", "prof"}, Duration: 120},
+	}
+	expectedEvents := []models.PlannerEvent{
+		{
+			DayOfWeek:         "l",
+			StartTime:         "12:00",
+			EndTime:           "13:59",
+			UsersAvailable:    3,
+			Attendees:         []string{"a", "b", "prof"},
+			Duration:          120,
+			RequiredAttendees: []string{"prof"},
+			OptionalAttendees: []string{"a", "b"},
+		},
+		{
+			DayOfWeek:         "l",
+			StartTime:         "06:00",
+			EndTime:           "07:59",
+			UsersAvailable:    2,
+			Attendees:         []string{"b", "prof"},
+			Duration:          120,
+			RequiredAttendees: []string{"prof"},
+			OptionalAttendees: []string{"b"},
+		},
+		{
+			DayOfWeek:         "m",
+			StartTime:         "08:00",
+			EndTime:           "09:59",
+			UsersAvailable:    2,
+			Attendees:         []string{"a", "prof"},
+			Duration:          120,
+			RequiredAttendees: []string{"prof"},
+			OptionalAttendees: []string{"a"},
+		},
+		{
+			DayOfWeek:         "l",
+			StartTime:         "08:00",
+			EndTime:           "09:59",
+			UsersAvailable:    1,
+			Attendees:         []string{"prof"},
+			Duration:          120,
+			RequiredAttendees: []string{"prof"},
+			OptionalAttendees: []string{},
+		},
+	}
+
+	actualEvents := applyAttendeeRoles(plannerEvents, []string{"prof"})
+	if !reflect.DeepEqual(actualEvents, expectedEvents) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedEvents, actualEvents)
+	} else {
+		t.Logf("SUCCESS Expected: %v, Got: %v", expectedEvents, actualEvents)
+	}
+}
+
+func TestGetAvailableTimeSlotsWithRequiredAttendees(t *testing.T) {
+	// Caso de prueba: el usuario obligatorio está ocupado toda la semana salvo el lunes en la mañana
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	schedules := []models.ScheduleModel{
+		{
+			ID:        "prof",
+			Required:  true,
+			Monday:    []models.TimeBlock{{StartMinute: 720, EndMinute: 1439}},
+			Tuesday:   busyDay,
+			Wednesday: busyDay,
+			Thursday:  busyDay,
+			Friday:    busyDay,
+			Saturday:  busyDay,
+			Sunday:    busyDay,
+		},
+		{ID: "student", Monday: []models.TimeBlock{{StartMinute: 0, EndMinute: 479}}},
+	}
+	expectedSlots := []models.PlannerEvent{
+		{
+			DayOfWeek:         "l",
+			StartTime:         "08:00",
+			EndTime:           "11:59",
+			UsersAvailable:    2,
+			Attendees:         []string{"prof", "student"},
+			Duration:          240,
+			RequiredAttendees: []string{"prof"},
+			OptionalAttendees: []string{"student"},
+		},
+		{
+			DayOfWeek:         "l",
+			StartTime:         "00:00",
+			EndTime:           "07:59",
+			UsersAvailable:    1,
+			Attendees:         []string{"prof"},
+			Duration:          480,
+			RequiredAttendees: []string{"prof"},
+			OptionalAttendees: []string{},
+		},
+	}
+
+	actualSlots := mustGetAvailableTimeSlots(t, schedules, models.PlannerOptions{})
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"Planner/models"
+)
+
+// CheckProposal verifica si los usuarios pueden asistir a la reunión propuesta en options.Proposal, usando los mismos
+// horarios normalizados que GetAvailableTimeSlots. Para cada usuario indica los minutos de la reunión en los que está
+// ocupado y los orígenes de esos bloques, que solo se conocen si los horarios se construyeron en el modo de
+// explicación. La reunión es viable si asisten todos los usuarios obligatorios y al menos el mínimo de asistentes
+func CheckProposal(ctx context.Context, schedules []models.ScheduleModel, options models.PlannerOptions) (models.ProposalCheck, error) {
+	proposal := options.Proposal
+	day := proposal.DayOfWeek
+	if proposal.Date != "" {
+		day = proposal.Date
+	}
+
+	// Una reunión que termina antes de su inicio continúa hasta el día siguiente
+	block, err := convertToTimeBlock(models.Event{StartTime: proposal.StartTime, EndTime: proposal.EndTime})
+	if err != nil {
+		return models.ProposalCheck{}, err
+	}
+
+	// Ordenar los usuarios por ID para que la respuesta no dependa del orden de la solicitud
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].ID < schedules[j].ID
+	})
+
+	check := models.ProposalCheck{Duration: block.EndMinute - block.StartMinute + 1, Users: []models.UserCheck{}}
+	requiredAvailable := true
+	for _, schedule := range schedules {
+		if err := checkContext(ctx); err != nil {
+			return models.ProposalCheck{}, err
+		}
+
+		// Sumar los minutos ocupados de la reunión. Los bloques normalizados no se solapan entre sí
+		normalizedSchedule := normalizeSchedule(schedule)
+		overlaps := getOverlappingBlocks(&normalizedSchedule, day, block.StartMinute, block.EndMinute)
+		userCheck := models.UserCheck{
+			UserID:    schedule.ID,
+			Available: len(overlaps) == 0,
+			Required:  schedule.Required,
+			Tentative: len(overlaps) > 0,
+			Conflicts: getBlockConflicts(overlaps),
+		}
+		for _, overlap := range overlaps {
+			userCheck.OverlapMinutes += overlap.EndMinute - overlap.StartMinute + 1
+			userCheck.Tentative = userCheck.Tentative && overlap.Tentative
+		}
+
+		if userCheck.Available {
+			check.UsersAvailable++
+		} else if schedule.Required {
+			requiredAvailable = false
+		}
+		check.Users = append(check.Users, userCheck)
+	}
+
+	// Una reunión necesita al menos un asistente
+	minAttendees := max(getMinAttendees(options, getRequiredUsers(schedules), len(schedules)), 1)
+	check.Available = requiredAvailable && check.UsersAvailable >= minAttendees
+
+	return check, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestCheckProposal(t *testing.T) {
+	// Caso de prueba: un usuario ocupado durante parte de la reunión, otro con un evento tentativo y otro libre
+	standup := models.Event{DayOfWeek: "l", StartTime: "0900", EndTime: "0959"}
+	gym := models.Event{DayOfWeek: "l", StartTime: "0930", EndTime: "1029", Availability: "tentative"}
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {standup},
+			"2": {gym},
+			"3": {},
+		},
+		Options: models.PlannerOptions{
+			Explain:  true,
+			Proposal: &models.ProposalOptions{DayOfWeek: "l", StartTime: "0930", EndTime: "1059"},
+		},
+	}
+
+	expectedCheck := models.ProposalCheck{
+		Available:      false,
+		UsersAvailable: 1,
+		Duration:       90,
+		Users: []models.UserCheck{
+			{UserID: "1", OverlapMinutes: 30, Conflicts: []models.BlockSource{{Reason: models.ReasonEvent, Event: &standup}}},
+			{UserID: "2", Tentative: true, OverlapMinutes: 60, Conflicts: []models.BlockSource{{Reason: models.ReasonEvent, Event: &gym}}},
+			{UserID: "3", Available: true, Conflicts: []models.BlockSource{}},
+		},
+	}
+	assertProposalCheck(t, request, expectedCheck)
+
+	// Si solo el usuario 3 es obligatorio, la reunión es viable
+	request.Required = []string{"3"}
+	expectedCheck.Available = true
+	expectedCheck.Users[2].Required = true
+	assertProposalCheck(t, request, expectedCheck)
+}
+
+func TestCheckProposalOvernight(t *testing.T) {
+	// Caso de prueba: una reunión del domingo que cruza la medianoche y un evento del lunes a primera hora
+	early := models.Event{DayOfWeek: "l", StartTime: "0000", EndTime: "0014"}
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{"1": {early}},
+		Options: models.PlannerOptions{
+			Explain:  true,
+			Proposal: &models.ProposalOptions{DayOfWeek: "d", StartTime: "2330", EndTime: "0029"},
+		},
+	}
+
+	expectedCheck := models.ProposalCheck{
+		UsersAvailable: 0,
+		Duration:       60,
+		Users: []models.UserCheck{
+			{UserID: "1", OverlapMinutes: 15, Conflicts: []models.BlockSource{{Reason: models.ReasonEvent, Event: &early}}},
+		},
+	}
+	assertProposalCheck(t, request, expectedCheck)
+}
+
+// Función auxiliar para aserciones de la verificación de una reunión propuesta
+func assertProposalCheck(t *testing.T, request models.PlannerRequest, expectedCheck models.ProposalCheck) {
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	actualCheck, err := CheckProposal(context.Background(), schedules, request.Options)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(actualCheck, expectedCheck) {
+		t.Errorf("ERROR Expected: %+v, Got: %+v", expectedCheck, actualCheck)
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"Planner/models"
+)
+
+// GetAttendeeWindows obtiene, para cada grupo de asistentes de options.Coalesce.Sets, las ventanas continuas más
+// largas en las que todo el grupo está libre, aunque durante parte de la ventana también lo estén otros usuarios.
+// Sin grupos en la solicitud, se usan los grupos de las ventanas de reunión de /planner
+func GetAttendeeWindows(ctx context.Context, schedules []models.ScheduleModel, options models.PlannerOptions) ([]models.AttendeeWindows, error) {
+
+	// Barrer los horarios de todos los usuarios para obtener los segmentos de cada día
+	days, segmentsByDay, err := sweepSchedules(ctx, schedules, options)
+	if err != nil {
+		return nil, err
+	}
+	timelines, circular := getTimelines(days, segmentsByDay, options)
+
+	// Tabla de IDs de los usuarios, igual a la de los segmentos de la barrida
+	usersId := make([]string, len(schedules))
+	for i, schedule := range schedules {
+		usersId[i] = schedule.ID
+	}
+	ids := newUserTable(usersId)
+
+	// Obtener los grupos de asistentes de la solicitud o, si no los envía, los de las ventanas de reunión
+	var sets []userSet
+	for _, set := range options.Coalesce.Sets {
+		sets = append(sets, newUserSetOf(ids, set))
+	}
+	if len(options.Coalesce.Sets) == 0 {
+		requiredUsers := getRequiredUsers(schedules)
+		if sets, err = findWindowAttendeeSets(ctx, timelines, getMinAttendees(options, requiredUsers, len(schedules)), newUserSetOf(ids, requiredUsers), options, circular); err != nil {
+			return nil, err
+		}
+	}
+
+	// Buscar las ventanas de cada grupo en cada línea de tiempo
+	attendeeWindows := make([]models.AttendeeWindows, 0, len(sets))
+	for _, set := range sets {
+		group := models.AttendeeWindows{Attendees: set.toIDs(), Size: set.count(), Windows: []models.PlannerEvent{}}
+		for _, segments := range timelines {
+			windows, err := findAttendeeSetWindows(ctx, segments, set, options.Duration, options.Granularity, circular)
+			if err != nil {
+				return nil, err
+			}
+			for _, window := range windows {
+				if options.Duration > 0 {
+					group.Windows = append(group.Windows, newWindowEvent(window, options.Duration, options.Granularity))
+				} else {
+					group.Windows = append(group.Windows, newPlannerEvent(window))
+				}
+			}
+		}
+		sortSlots(group.Windows, models.SortByTime)
+		attendeeWindows = append(attendeeWindows, group)
+	}
+
+	return attendeeWindows, nil
+}
+
+// Función para obtener los grupos distintos de asistentes de las ventanas de reunión de las líneas de tiempo que
+// incluyen a todos los usuarios obligatorios, ordenados de mayor a menor tamaño y luego por sus IDs
+func findWindowAttendeeSets(ctx context.Context, timelines [][]availabilitySegment, minAttendees int, required userSet, options models.PlannerOptions, circular bool) ([]userSet, error) {
+	var sets []userSet
+	seen := make(map[string]bool)
+	for _, segments := range timelines {
+		windows, err := findMeetingWindows(ctx, segments, minAttendees, max(options.Duration, 1), options.Granularity, circular)
+		if err != nil {
+			return nil, err
+		}
+		for _, window := range windows {
+			key := strings.Join(window.attendees.toIDs(), ",")
+			if !seen[key] && required.isSubsetOf(window.attendees) {
+				seen[key] = true
+				sets = append(sets, window.attendees)
+			}
+		}
+	}
+
+	sort.SliceStable(sets, func(i, j int) bool {
+		if sets[i].count() != sets[j].count() {
+			return sets[i].count() > sets[j].count()
+		}
+		return strings.Join(sets[i].toIDs(), ",") < strings.Join(sets[j].toIDs(), ",")
+	})
+	return sets, nil
+}
+
+// Función para encontrar las ventanas maximales de una línea de tiempo en las que todos los usuarios de set están
+// libres, alineadas a la cuadrícula y de al menos minDuration minutos. Los asistentes de cada ventana son los
+// usuarios libres durante toda la ventana, que incluyen a set. Si la línea de tiempo es circular, las ventanas
+// pueden continuar desde el último segmento hasta el primero
+func findAttendeeSetWindows(ctx context.Context, segments []availabilitySegment, set userSet, minDuration int, granularity int, circular bool) ([]availabilitySegment, error) {
+	if len(segments) == 0 {
+		return nil, nil
+	}
+	isFree := func(i int) bool {
+		return set.isSubsetOf(segments[i%len(segments)].attendees)
+	}
+
+	// En una línea de tiempo circular se inicia después de un segmento donde el grupo no está libre, para no
+	// dividir la ventana que da la vuelta. Si el grupo está libre en todos, la ventana es la semana completa
+	first := 0
+	if circular {
+		first = -1
+		for i := range segments {
+			if !isFree(i) {
+				first = i + 1
+				break
+			}
+		}
+		if first < 0 {
+			attendees, reachable := segments[0].attendees, segments[0].attendees.union(segments[0].tentative)
+			for _, segment := range segments[1:] {
+				attendees = attendees.intersect(segment.attendees)
+				reachable = reachable.intersect(segment.attendees.union(segment.tentative))
+			}
+			return snapWindows([]availabilitySegment{newWindow(segments[0], segments[len(segments)-1], attendees, reachable)}, minDuration, granularity), nil
+		}
+	}
+
+	// Recorrer los segmentos una vez, abriendo una ventana donde el grupo empieza a estar libre y cerrándola
+	// donde deja de estarlo
+	var windows []availabilitySegment
+	start := -1
+	var attendees, reachable userSet
+	for j := first; j <= first+len(segments); j++ {
+		if j%contextCheckInterval == 0 {
+			if err := checkContext(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		// El último paso solo cierra la ventana que sigue abierta al final de una línea de tiempo lineal
+		if j < first+len(segments) && isFree(j) {
+			segment := segments[j%len(segments)]
+			if start < 0 {
+				start, attendees, reachable = j, segment.attendees, segment.attendees.union(segment.tentative)
+			} else {
+				attendees = attendees.intersect(segment.attendees)
+				reachable = reachable.intersect(segment.attendees.union(segment.tentative))
+			}
+			continue
+		}
+		if start >= 0 {
+			windows = append(windows, newWindow(segments[start%len(segments)], segments[(j-1)%len(segments)], attendees, reachable))
+			start = -1
+		}
+	}
+
+	return snapWindows(windows, minDuration, granularity), nil
+}
+
+// Función auxiliar para alinear las ventanas a la cuadrícula y descartar las que quedan con menos de minDuration minutos
+func snapWindows(windows []availabilitySegment, minDuration int, granularity int) []availabilitySegment {
+	snappedWindows := make([]availabilitySegment, 0, len(windows))
+	for _, window := range windows {
+		if window, ok := snapSegment(window, granularity); ok && window.end-window.start >= minDuration {
+			snappedWindows = append(snappedWindows, window)
+		}
+	}
+	return snappedWindows
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestGetAttendeeWindows(t *testing.T) {
+	// Caso de prueba: los usuarios 1 y 2 están libres el lunes de 09:00 a 12:00 y el usuario 3 de 10:00 a 11:00
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	newSchedule := func(id string, monday []models.TimeBlock) models.ScheduleModel {
+		return models.ScheduleModel{ID: id, Monday: monday, Tuesday: busyDay, Wednesday: busyDay, Thursday: busyDay, Friday: busyDay, Saturday: busyDay, Sunday: busyDay}
+	}
+	newSchedules := func() []models.ScheduleModel {
+		return []models.ScheduleModel{
+			newSchedule("1", []models.TimeBlock{{StartMinute: 0, EndMinute: 539}, {StartMinute: 720, EndMinute: 1439}}),
+			newSchedule("2", []models.TimeBlock{{StartMinute: 0, EndMinute: 539}, {StartMinute: 720, EndMinute: 1439}}),
+			newSchedule("3", []models.TimeBlock{{StartMinute: 0, EndMinute: 599}, {StartMinute: 660, EndMinute: 1439}}),
+		}
+	}
+
+	// Sin grupos en la solicitud, se usan los grupos de las ventanas de reunión, primero el más grande. La ventana
+	// de los usuarios 1 y 2 incluye la hora en la que el usuario 3 también está libre
+	options := models.PlannerOptions{Duration: 30, MinAttendees: 2, Coalesce: &models.CoalesceOptions{}}
+	expectedWindows := []string{
+		"[1 2 3]: l 10:00-10:59 [1 2 3]",
+		"[1 2]: l 09:00-11:59 [1 2]",
+	}
+	assertAttendeeWindows(t, newSchedules(), options, expectedWindows)
+
+	// Con grupos en la solicitud, se respeta su orden y los asistentes de cada ventana incluyen a los demás usuarios libres
+	options.Coalesce.Sets = [][]string{{"3", "1"}, {"2"}}
+	expectedWindows = []string{
+		"[1 3]: l 10:00-10:59 [1 2 3]",
+		"[2]: l 09:00-11:59 [1 2]",
+	}
+	assertAttendeeWindows(t, newSchedules(), options, expectedWindows)
+
+	// Un grupo sin ventanas suficientemente largas se retorna sin ventanas
+	options.Duration = 90
+	options.Coalesce.Sets = [][]string{{"3"}}
+	assertAttendeeWindows(t, newSchedules(), options, []string{"[3]:"})
+}
+
+func TestGetAttendeeWindowsOnCircularWeek(t *testing.T) {
+	// Caso de prueba: el usuario 1 está libre del domingo a las 22:00 al lunes a las 02:00 y el usuario 2 toda la semana
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	schedules := []models.ScheduleModel{
+		{
+			ID:      "1",
+			Monday:  []models.TimeBlock{{StartMinute: 120, EndMinute: 1439}},
+			Tuesday: busyDay, Wednesday: busyDay, Thursday: busyDay, Friday: busyDay, Saturday: busyDay,
+			Sunday: []models.TimeBlock{{StartMinute: 0, EndMinute: 1319}},
+		},
+		{ID: "2"},
+	}
+
+	// La ventana del grupo da la vuelta a la semana, y la del usuario 2 es la semana completa
+	options := models.PlannerOptions{WeekWrap: true, Coalesce: &models.CoalesceOptions{Sets: [][]string{{"1"}, {"2"}}}}
+	expectedWindows := []string{
+		"[1]: d 22:00-01:59 [1 2]",
+		"[2]: l 02:00-01:59 [2]",
+	}
+	assertAttendeeWindows(t, schedules, options, expectedWindows)
+}
+
+// Función auxiliar para aserciones de las ventanas de cada grupo, descritas como "grupo: día inicio-fin asistentes"
+func assertAttendeeWindows(t *testing.T, schedules []models.ScheduleModel, options models.PlannerOptions, expectedWindows []string) {
+	attendeeWindows, err := GetAttendeeWindows(context.Background(), schedules, options)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	actualWindows := []string{}
+	for _, group := range attendeeWindows {
+		description := fmt.Sprintf("%v:", group.Attendees)
+		if group.Size != len(group.Attendees) {
+			t.Errorf("ERROR Expected size %d, Got: %d", len(group.Attendees), group.Size)
+		}
+		for _, window := range group.Windows {
+			description += fmt.Sprintf(" %s %s-%s %v", window.DayOfWeek, window.StartTime, window.EndTime, window.Attendees)
+		}
+		actualWindows = append(actualWindows, description)
+	}
+	if !reflect.DeepEqual(actualWindows, expectedWindows) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedWindows, actualWindows)
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"Planner/models"
+)
+
+// DateLayout es el formato de las fechas de la solicitud y de la respuesta
+const DateLayout = "2006-01-02"
+
+// Función para obtener las fechas de un rango en orden, incluyendo ambos extremos
+func getDatesInRange(dateRange models.DateRange) []string {
+	from, err := time.Parse(DateLayout, dateRange.From)
+	if err != nil {
+		return nil
+	}
+	to, err := time.Parse(DateLayout, dateRange.To)
+	if err != nil {
+		return nil
+	}
+
+	var dates []string
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date.Format(DateLayout))
+	}
+	return dates
+}
+
+// Función para obtener las fechas ordenadas de los horarios de los usuarios
+func getScheduleDates(schedules []models.ScheduleModel) []string {
+	datesSet := make(map[string]bool)
+	for _, schedule := range schedules {
+		for date := range schedule.Dates {
+			datesSet[date] = true
+		}
+	}
+
+	dates := make([]string, 0, len(datesSet))
+	for date := range datesSet {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates
+}
+
+// Función auxiliar para verificar si un día es una fecha "AAAA-MM-DD" en lugar de un día de la semana
+func isDate(day string) bool {
+	_, err := time.Parse(DateLayout, day)
+	return err == nil
+}
+
+// GetDayOfWeekOfDate obtiene el día de la semana ("l" a "d") de una fecha "AAAA-MM-DD", o "" si la fecha no es válida.
+// Lo usan también los middlewares para validar que el día de la semana de un evento coincida con su fecha
+func GetDayOfWeekOfDate(date string) string {
+	parsedDate, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return ""
+	}
+
+	// time.Weekday inicia el domingo, mientras que weekDays inicia el lunes
+	return weekDays[(int(parsedDate.Weekday())+6)%len(weekDays)]
+}
+
+// Función auxiliar para obtener la fecha que está n días después de date
+func shiftDate(date string, n int) string {
+	parsedDate, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return date
+	}
+	return parsedDate.AddDate(0, 0, n).Format(DateLayout)
+}
+
+// Función auxiliar para separar un día en su día de la semana y, si es una fecha, la fecha
+func describeDay(day string) (dayOfWeek string, date string) {
+	if isDate(day) {
+		return GetDayOfWeekOfDate(day), day
+	}
+	return day, ""
+}
+
+// Función para calcular los TimeBlocks de cada fecha del rango a partir del horario semanal del usuario,
+// quitando el tiempo de los eventos cancelados y agregando los eventos puntuales de esa fecha. En el modo de
+// explicación, los bloques de los eventos puntuales registran el evento como su origen
+func addDateTimeBlocks(schedule *models.ScheduleModel, events []models.Event, dates []string, explain bool) error {
+	// Normalizar el horario semanal para que los eventos que cruzan la medianoche ya estén divididos
+	template := normalizeSchedule(*schedule)
+
+	// Agrupar los eventos puntuales y las cancelaciones por fecha, dividiendo los que cruzan la medianoche
+	busyByDate := make(map[string][]models.TimeBlock)
+	cancelledByDate := make(map[string][]models.TimeBlock)
+	for _, event := range events {
+		if event.Date == "" {
+			continue
+		}
+
+		block, err := convertToBusyBlock(event, explain)
+		if err != nil {
+			return err
+		}
+
+		blocksByDate := busyByDate
+		if event.Cancelled {
+			blocksByDate = cancelledByDate
+		}
+		if block.EndMinute >= 1440 {
+			nextDate := shiftDate(event.Date, 1)
+			blocksByDate[nextDate] = append(blocksByDate[nextDate], models.TimeBlock{StartMinute: 0, EndMinute: block.EndMinute - 1440, Tentative: block.Tentative, Sources: block.Sources})
+			block.EndMinute = 1440 - 1
+		}
+		blocksByDate[event.Date] = append(blocksByDate[event.Date], block)
+	}
+
+	// Combinar el horario semanal del día de la semana de cada fecha con sus eventos puntuales
+	schedule.Dates = make(map[string][]models.TimeBlock, len(dates))
+	for _, date := range dates {
+		templateBlocks, err := getDayTimeBlocks(&template, GetDayOfWeekOfDate(date))
+		if err != nil {
+			return err
+		}
+		dateBlocks := subtractBlocks(*templateBlocks, cancelledByDate[date])
+		schedule.Dates[date] = append(dateBlocks, busyByDate[date]...)
+	}
+
+	return nil
+}
+
+// Función para quitar de un conjunto de TimeBlocks los minutos cubiertos por otro conjunto
+func subtractBlocks(timeBlocks []models.TimeBlock, removedBlocks []models.TimeBlock) []models.TimeBlock {
+	var remainingBlocks []models.TimeBlock
+	freeBlocks := invertBlocks(removedBlocks)
+	for _, block := range timeBlocks {
+		for _, free := range freeBlocks {
+			start := max(block.StartMinute, free.StartMinute)
+			end := min(block.EndMinute, free.EndMinute)
+			if start <= end {
+				remainingBlocks = append(remainingBlocks, models.TimeBlock{StartMinute: start, EndMinute: end, Tentative: block.Tentative, Sources: block.Sources})
+			}
+		}
+	}
+	return remainingBlocks
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestGetDatesInRange(t *testing.T) {
+	// Caso de prueba: rango que cruza el fin de mes
+	expectedDates := []string{"2024-04-29", "2024-04-30", "2024-05-01"}
+	actualDates := getDatesInRange(models.DateRange{From: "2024-04-29", To: "2024-05-01"})
+	if !reflect.DeepEqual(actualDates, expectedDates) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedDates, actualDates)
+	}
+
+	// Caso de prueba: el 14 de mayo de 2024 es martes
+	if day := GetDayOfWeekOfDate("2024-05-14"); day != "m" {
+		t.Errorf("ERROR Expected: m, Got: %v", day)
+	}
+}
+
+func TestBuildSchedulesWithDateRange(t *testing.T) {
+	// Caso de prueba: una clase semanal de martes cancelada una semana, un examen puntual y un turno nocturno
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {
+				{DayOfWeek: "m", StartTime: "0800", EndTime: "1000"},
+				{DayOfWeek: "l", StartTime: "2300", EndTime: "0100"},
+				{Date: "2024-05-14", StartTime: "0800", EndTime: "1000", Cancelled: true},
+				{Date: "2024-05-14", StartTime: "1400", EndTime: "1600"},
+				{Date: "2024-05-15", DayOfWeek: "i", StartTime: "2200", EndTime: "0030"},
+			},
+		},
+		Options: models.PlannerOptions{
+			DateRange: &models.DateRange{From: "2024-05-13", To: "2024-05-21"},
+		},
+	}
+
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	expectedDates := map[string][]models.TimeBlock{
+		"2024-05-13": {{StartMinute: 1380, EndMinute: 1439}},
+		"2024-05-14": {{StartMinute: 0, EndMinute: 60}, {StartMinute: 840, EndMinute: 960}},
+		"2024-05-15": {{StartMinute: 1320, EndMinute: 1439}},
+		"2024-05-16": {{StartMinute: 0, EndMinute: 30}},
+		"2024-05-17": nil,
+		"2024-05-18": nil,
+		"2024-05-19": nil,
+		"2024-05-20": {{StartMinute: 1380, EndMinute: 1439}},
+		"2024-05-21": {{StartMinute: 0, EndMinute: 60}, {StartMinute: 480, EndMinute: 600}},
+	}
+	if !reflect.DeepEqual(schedules[0].Dates, expectedDates) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedDates, schedules[0].Dates)
+	}
+
+	// Los slots se reportan con su fecha concreta, y la clase cancelada deja libre la mañana del martes
+	request.Options.Duration = 600
+	request.Options.DateRange.To = "2024-05-14"
+	expectedSlots := []models.PlannerEvent{
+		{
+			DayOfWeek:       "l",
+			StartTime:       "00:00",
+			EndTime:         "22:59",
+			UsersAvailable:  1,
+			Attendees:       []string{"1"},
+			Duration:        1380,
+			LatestStartTime: "13:00",
+			Date:            "2024-05-13",
+		},
+		{
+			DayOfWeek:       "m",
+			StartTime:       "01:01",
+			EndTime:         "13:59",
+			UsersAvailable:  1,
+			Attendees:       []string{"1"},
+			Duration:        779,
+			LatestStartTime: "04:00",
+			Date:            "2024-05-14",
+		},
+	}
+	schedules, _ = BuildSchedules(context.Background(), request)
+	actualSlots := mustGetAvailableTimeSlots(t, schedules, request.Options)
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+}
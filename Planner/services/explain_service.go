@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"Planner/models"
+)
+
+// Función para agregar a cada slot los usuarios que no están libres durante todo el slot, junto con los orígenes
+// de sus bloques ocupados que se solapan con él. Los horarios deben estar normalizados, por lo que sus bloques ya
+// están divididos por día y fusionados, conservando los orígenes registrados al construirlos
+func explainSlots(ctx context.Context, slots []models.PlannerEvent, schedules []models.ScheduleModel) error {
+	for i := range slots {
+		if err := checkContext(ctx); err != nil {
+			return err
+		}
+
+		// Obtener el día (o fecha) de inicio del slot y sus minutos de inicio y fin (inclusive) desde la medianoche de ese día
+		day := slots[i].DayOfWeek
+		if slots[i].Date != "" {
+			day = slots[i].Date
+		}
+		start, err := convertToMinutes(strings.Replace(slots[i].StartTime, ":", "", 1))
+		if err != nil {
+			return err
+		}
+		end := start + slots[i].Duration - 1
+
+		isAttendee := make(map[string]bool, len(slots[i].Attendees))
+		for _, userID := range slots[i].Attendees {
+			isAttendee[userID] = true
+		}
+
+		for j := range schedules {
+			if isAttendee[schedules[j].ID] {
+				continue
+			}
+			slots[i].Absentees = append(slots[i].Absentees, models.Absentee{
+				UserID:    schedules[j].ID,
+				Conflicts: getBlockConflicts(getOverlappingBlocks(&schedules[j], day, start, end)),
+			})
+		}
+	}
+	return nil
+}
+
+// Función para obtener las partes de los bloques ocupados de un usuario que se solapan con los minutos start a end
+// (inclusive), contados desde la medianoche de day y en ese mismo sistema de minutos. El intervalo puede continuar
+// en los días siguientes
+func getOverlappingBlocks(schedule *models.ScheduleModel, day string, start int, end int) []models.TimeBlock {
+	var overlaps []models.TimeBlock
+	for offset := start / 1440; offset <= end/1440; offset++ {
+		for _, block := range getBusyBlocks(schedule, shiftDay(day, offset)) {
+			block.StartMinute = max(block.StartMinute+offset*1440, start)
+			block.EndMinute = min(block.EndMinute+offset*1440, end)
+			if block.StartMinute <= block.EndMinute {
+				overlaps = append(overlaps, block)
+			}
+		}
+	}
+	return overlaps
+}
+
+// Función para obtener los orígenes de unos bloques, sin repetir y en orden de aparición
+func getBlockConflicts(timeBlocks []models.TimeBlock) []models.BlockSource {
+	conflicts := []models.BlockSource{}
+	var seen []*models.BlockSource
+	for _, block := range timeBlocks {
+		for _, source := range block.Sources {
+			if !slices.Contains(seen, source) {
+				seen = append(seen, source)
+				conflicts = append(conflicts, *source)
+			}
+		}
+	}
+	return conflicts
+}
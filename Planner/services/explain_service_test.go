@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestExplainSlots(t *testing.T) {
+	// Caso de prueba: dos eventos que se solapan, un evento tentativo y un usuario fuera de su horario laboral
+	standup := models.Event{DayOfWeek: "l", StartTime: "0900", EndTime: "0959"}
+	review := models.Event{DayOfWeek: "l", StartTime: "0930", EndTime: "1029"}
+	gym := models.Event{DayOfWeek: "l", StartTime: "0900", EndTime: "0929", Availability: "tentative"}
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {standup, review},
+			"2": {gym},
+			"3": {},
+		},
+		WorkingHours: models.WorkingHours{
+			Users: map[string][]models.Event{"3": {{DayOfWeek: "l", StartTime: "1200", EndTime: "1759"}}},
+		},
+		Options: models.PlannerOptions{Explain: true},
+	}
+
+	// Los eventos fusionados en un mismo bloque explican juntos la ausencia del usuario 1
+	expectedAbsentees := []models.Absentee{
+		{UserID: "1", Conflicts: []models.BlockSource{{Reason: models.ReasonEvent, Event: &standup}, {Reason: models.ReasonEvent, Event: &review}}},
+		{UserID: "2", Conflicts: []models.BlockSource{{Reason: models.ReasonEvent, Event: &gym}}},
+		{UserID: "3", Conflicts: []models.BlockSource{{Reason: models.ReasonOutsideWorkingHours}}},
+	}
+	assertAbsentees(t, request, "l", "09:00", expectedAbsentees)
+
+	// Desde las 10:30 el usuario 1 está libre y solo falta el usuario 3
+	expectedAbsentees = []models.Absentee{{UserID: "3", Conflicts: []models.BlockSource{{Reason: models.ReasonOutsideWorkingHours}}}}
+	assertAbsentees(t, request, "l", "10:30", expectedAbsentees)
+
+	// Cuando todos asisten, el slot no tiene ausentes
+	assertAbsentees(t, request, "l", "12:00", nil)
+}
+
+func TestExplainSlotsWithDates(t *testing.T) {
+	// Caso de prueba: un evento semanal cancelado en parte el lunes y un evento puntual el martes
+	weekly := models.Event{DayOfWeek: "l", StartTime: "0900", EndTime: "0959"}
+	cancelled := models.Event{Date: "2024-06-03", StartTime: "0900", EndTime: "0929", Cancelled: true}
+	oneOff := models.Event{Date: "2024-06-04", StartTime: "1000", EndTime: "1059"}
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {weekly, cancelled, oneOff},
+			"2": {},
+		},
+		Options: models.PlannerOptions{Explain: true, DateRange: &models.DateRange{From: "2024-06-03", To: "2024-06-04"}},
+	}
+
+	// La parte que queda del evento semanal conserva su origen, mientras que la cancelación no aparece
+	expectedAbsentees := []models.Absentee{{UserID: "1", Conflicts: []models.BlockSource{{Reason: models.ReasonEvent, Event: &weekly}}}}
+	assertAbsentees(t, request, "2024-06-03", "09:30", expectedAbsentees)
+
+	expectedAbsentees = []models.Absentee{{UserID: "1", Conflicts: []models.BlockSource{{Reason: models.ReasonEvent, Event: &oneOff}}}}
+	assertAbsentees(t, request, "2024-06-04", "10:00", expectedAbsentees)
+}
+
+func TestExplainWithRecurringEvents(t *testing.T) {
+	// Caso de prueba: las ocurrencias de un evento recurrente, con y sin rango de fechas, reportan el evento de la
+	// solicitud con su regla y sus fechas excluidas
+	recurring := models.Event{Date: "2024-06-03", StartTime: "0900", EndTime: "0959", RRule: "FREQ=WEEKLY;BYDAY=MO,TU", ExDates: []string{"2024-06-11"}}
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {recurring},
+			"2": {},
+		},
+		Options: models.PlannerOptions{Explain: true, DateRange: &models.DateRange{From: "2024-06-03", To: "2024-06-04"}},
+	}
+	expectedAbsentees := []models.Absentee{{UserID: "1", Conflicts: []models.BlockSource{{Reason: models.ReasonEvent, Event: &recurring}}}}
+	assertAbsentees(t, request, "2024-06-04", "09:00", expectedAbsentees)
+
+	recurring.Date, recurring.ExDates = "", nil
+	recurring.DayOfWeek = "l"
+	request.Users["1"] = []models.Event{recurring}
+	request.Options.DateRange = nil
+	assertAbsentees(t, request, "m", "09:00", expectedAbsentees)
+}
+
+// Función auxiliar para aserciones de los ausentes del slot que inicia en un día (o fecha) y una hora
+func assertAbsentees(t *testing.T, request models.PlannerRequest, day string, startTime string, expectedAbsentees []models.Absentee) {
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	for _, slot := range mustGetAvailableTimeSlots(t, schedules, request.Options) {
+		if (slot.DayOfWeek == day || slot.Date == day) && slot.StartTime == startTime {
+			if !reflect.DeepEqual(slot.Absentees, expectedAbsentees) {
+				t.Errorf("ERROR Expected: %v, Got: %v", expectedAbsentees, slot.Absentees)
+			}
+			return
+		}
+	}
+	t.Errorf("ERROR Expected a slot on '%s' at %s", day, startTime)
+}
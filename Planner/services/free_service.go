@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"Planner/models"
+)
+
+// GetUsersStatus obtiene los usuarios libres y ocupados en el momento options.At. Para cada usuario libre indica el
+// último minuto libre antes de su siguiente bloque ocupado, y para cada ocupado el primer minuto en que vuelve a
+// estar libre. En la semana genérica se busca hasta una semana después; con un rango de fechas, hasta su última
+// fecha. Si el usuario no cambia de estado en ese horizonte, el campo se omite
+func GetUsersStatus(ctx context.Context, schedules []models.ScheduleModel, options models.PlannerOptions) ([]models.UserStatus, []models.UserStatus, error) {
+	day := options.At.DayOfWeek
+	if options.At.Date != "" {
+		day = options.At.Date
+	}
+	at, err := convertToMinutes(options.At.Time)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Días a revisar a partir del día consultado: la semana genérica da la vuelta hasta el mismo día de la semana
+	// siguiente, mientras que un rango de fechas termina en su última fecha
+	days := make([]string, 0, len(weekDays)+1)
+	if isDate(day) {
+		for _, date := range getScheduleDates(schedules) {
+			if date >= day {
+				days = append(days, date)
+			}
+		}
+	} else {
+		for offset := 0; offset <= len(weekDays); offset++ {
+			days = append(days, shiftDay(day, offset))
+		}
+	}
+
+	// Ordenar los usuarios por ID para que la respuesta no dependa del orden de la solicitud
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].ID < schedules[j].ID
+	})
+
+	free, busy := []models.UserStatus{}, []models.UserStatus{}
+	for _, schedule := range schedules {
+		if err := checkContext(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		// Expresar los bloques de los días a revisar en minutos desde la medianoche del día consultado
+		normalizedSchedule := normalizeSchedule(schedule)
+		var blocks []models.TimeBlock
+		for offset, d := range days {
+			for _, block := range getBusyBlocks(&normalizedSchedule, d) {
+				block.StartMinute += offset * 1440
+				block.EndMinute += offset * 1440
+				blocks = append(blocks, block)
+			}
+		}
+
+		// Recorrer los bloques ordenados por inicio que terminan desde el minuto consultado: los que lo cubren y los
+		// contiguos a ellos, incluyendo los que continúan después de la medianoche, forman el bloque ocupado. Los
+		// bloques tentativos y ocupados no se combinan, así que pueden solaparse
+		status := models.UserStatus{UserID: schedule.ID, Tentative: true}
+		horizon := len(days) * 1440
+		end, next := -1, -1
+		for _, block := range blocks {
+			if block.EndMinute < at {
+				continue
+			}
+			if block.StartMinute > max(at, end+1) {
+				next = block.StartMinute
+				break
+			}
+			end = max(end, block.EndMinute)
+			status.Tentative = status.Tentative && block.Tentative
+		}
+
+		// Libre: el estado dura hasta el inicio del siguiente bloque
+		if end < 0 {
+			status.Tentative = false
+			if next >= 0 {
+				status.FreeUntil = newMoment(day, next-1)
+			}
+			free = append(free, status)
+			continue
+		}
+
+		// Ocupado: el estado dura hasta el final de los bloques contiguos, y solo es tentativo si todos lo son
+		if end+1 < horizon {
+			status.FreeFrom = newMoment(day, end+1)
+		}
+		busy = append(busy, status)
+	}
+
+	return free, busy, nil
+}
+
+// Función auxiliar para crear un Moment a partir de los minutos desde la medianoche de day
+func newMoment(day string, minutes int) *models.Moment {
+	moment := &models.Moment{Time: convertToTimeString(minutes % 1440)}
+	moment.DayOfWeek, moment.Date = describeDay(shiftDay(day, minutes/1440))
+	return moment
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestGetUsersStatus(t *testing.T) {
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	newSchedules := func() []models.ScheduleModel {
+		return []models.ScheduleModel{
+			{ID: "e", Monday: busyDay, Tuesday: busyDay, Wednesday: busyDay, Thursday: busyDay, Friday: busyDay, Saturday: busyDay, Sunday: busyDay},
+			{ID: "a", Monday: []models.TimeBlock{{StartMinute: 540, EndMinute: 659}}},
+			{ID: "b", Monday: []models.TimeBlock{{StartMinute: 720, EndMinute: 779}}},
+			{ID: "c"},
+			{ID: "d", Monday: []models.TimeBlock{{StartMinute: 600, EndMinute: 629, Tentative: true}, {StartMinute: 630, EndMinute: 689}}},
+			{ID: "f", Monday: []models.TimeBlock{{StartMinute: 1380, EndMinute: 1439}}, Tuesday: []models.TimeBlock{{StartMinute: 0, EndMinute: 119}}},
+			{ID: "g", Monday: []models.TimeBlock{{StartMinute: 570, EndMinute: 629, Tentative: true}, {StartMinute: 600, EndMinute: 659, Tentative: true}}},
+			{ID: "h", Monday: []models.TimeBlock{{StartMinute: 540, EndMinute: 659, Tentative: true}, {StartMinute: 570, EndMinute: 599}}},
+		}
+	}
+
+	// Caso de prueba: el lunes a las 10:00, "a" sale a las 11:00, "d" tiene un bloque tentativo seguido de uno
+	// ocupado, así que no está solo en bloques tentativos, "g" sí, "h" tiene un bloque ocupado que ya terminó
+	// dentro de uno tentativo, "b" está libre hasta las 11:59, "c" toda la semana y "e" nunca
+	options := models.PlannerOptions{At: &models.AtOptions{DayOfWeek: "l", Time: "1000"}}
+	expectedFree := []models.UserStatus{
+		{UserID: "b", FreeUntil: &models.Moment{DayOfWeek: "l", Time: "11:59"}},
+		{UserID: "c"},
+		{UserID: "f", FreeUntil: &models.Moment{DayOfWeek: "l", Time: "22:59"}},
+	}
+	expectedBusy := []models.UserStatus{
+		{UserID: "a", FreeFrom: &models.Moment{DayOfWeek: "l", Time: "11:00"}},
+		{UserID: "d", FreeFrom: &models.Moment{DayOfWeek: "l", Time: "11:30"}},
+		{UserID: "e"},
+		{UserID: "g", Tentative: true, FreeFrom: &models.Moment{DayOfWeek: "l", Time: "11:00"}},
+		{UserID: "h", Tentative: true, FreeFrom: &models.Moment{DayOfWeek: "l", Time: "11:00"}},
+	}
+	assertUsersStatus(t, newSchedules(), options, expectedFree, expectedBusy)
+
+	// Caso de prueba: el lunes a las 23:30, "f" sigue ocupado después de la medianoche y "b" está libre hasta el
+	// lunes siguiente, dando la vuelta a la semana
+	options.At.Time = "2330"
+	expectedFree = []models.UserStatus{
+		{UserID: "a", FreeUntil: &models.Moment{DayOfWeek: "l", Time: "08:59"}},
+		{UserID: "b", FreeUntil: &models.Moment{DayOfWeek: "l", Time: "11:59"}},
+		{UserID: "c"},
+		{UserID: "d", FreeUntil: &models.Moment{DayOfWeek: "l", Time: "09:59"}},
+		{UserID: "g", FreeUntil: &models.Moment{DayOfWeek: "l", Time: "09:29"}},
+		{UserID: "h", FreeUntil: &models.Moment{DayOfWeek: "l", Time: "08:59"}},
+	}
+	expectedBusy = []models.UserStatus{
+		{UserID: "e"},
+		{UserID: "f", FreeFrom: &models.Moment{DayOfWeek: "m", Time: "02:00"}},
+	}
+	assertUsersStatus(t, newSchedules(), options, expectedFree, expectedBusy)
+}
+
+func TestGetUsersStatusWithDates(t *testing.T) {
+	// Caso de prueba: en un rango de fechas solo se revisa hasta la última fecha
+	schedules := []models.ScheduleModel{
+		{ID: "a", Dates: map[string][]models.TimeBlock{"2024-03-04": {{StartMinute: 600, EndMinute: 659}}, "2024-03-05": {}}},
+		{ID: "b", Dates: map[string][]models.TimeBlock{"2024-03-04": {}, "2024-03-05": {{StartMinute: 0, EndMinute: 1439}}}},
+	}
+	options := models.PlannerOptions{
+		DateRange: &models.DateRange{From: "2024-03-04", To: "2024-03-05"},
+		At:        &models.AtOptions{Date: "2024-03-04", Time: "1015"},
+	}
+	expectedFree := []models.UserStatus{
+		{UserID: "b", FreeUntil: &models.Moment{DayOfWeek: "l", Date: "2024-03-04", Time: "23:59"}},
+	}
+	expectedBusy := []models.UserStatus{
+		{UserID: "a", FreeFrom: &models.Moment{DayOfWeek: "l", Date: "2024-03-04", Time: "11:00"}},
+	}
+	assertUsersStatus(t, schedules, options, expectedFree, expectedBusy)
+}
+
+// Función auxiliar para aserciones de los usuarios libres y ocupados
+func assertUsersStatus(t *testing.T, schedules []models.ScheduleModel, options models.PlannerOptions, expectedFree []models.UserStatus, expectedBusy []models.UserStatus) {
+	free, busy, err := GetUsersStatus(context.Background(), schedules, options)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(free, expectedFree) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedFree, free)
+	}
+	if !reflect.DeepEqual(busy, expectedBusy) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedBusy, busy)
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"Planner/models"
+	"context"
+)
+
+// Resolución por defecto del mapa de calor en minutos
+const defaultHeatmapResolution = 15
+
+// GetAvailabilityHeatmap obtiene, para cada día (o fecha) a planificar, el número de usuarios libres durante todo
+// cada intervalo de options.Heatmap.Resolution minutos, y opcionalmente sus IDs
+func GetAvailabilityHeatmap(ctx context.Context, schedules []models.ScheduleModel, options models.PlannerOptions) ([]models.HeatmapDay, error) {
+	resolution := options.Heatmap.Resolution
+	if resolution == 0 {
+		resolution = defaultHeatmapResolution
+	}
+
+	// Barrer los horarios de todos los usuarios para obtener los segmentos de cada día
+	days, segmentsByDay, err := sweepSchedules(ctx, schedules, options)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := make([]models.HeatmapDay, 0, len(days))
+	for _, day := range days {
+		heatmapDay := models.HeatmapDay{Resolution: resolution, Counts: make([]int, 1440/resolution)}
+		heatmapDay.DayOfWeek, heatmapDay.Date = describeDay(day)
+		if options.Heatmap.IncludeAttendees {
+			heatmapDay.Attendees = make([][]string, len(heatmapDay.Counts))
+		}
+
+		// Intersectar los usuarios de los segmentos que se solapan con cada intervalo. Los segmentos están ordenados
+		// y cubren el día completo, así que basta con avanzar por ellos
+		segments := segmentsByDay[day]
+		first := 0
+		for bucket := range heatmapDay.Counts {
+			start, end := bucket*resolution, (bucket+1)*resolution
+			for first < len(segments) && segments[first].end <= start {
+				first++
+			}
+
+			var attendees userSet
+			for i := first; i < len(segments) && segments[i].start < end; i++ {
+				if i == first {
+					attendees = segments[i].attendees
+				} else {
+					attendees = attendees.intersect(segments[i].attendees)
+				}
+			}
+
+			heatmapDay.Counts[bucket] = attendees.count()
+			if options.Heatmap.IncludeAttendees {
+				heatmapDay.Attendees[bucket] = attendees.toIDs()
+			}
+		}
+
+		heatmap = append(heatmap, heatmapDay)
+	}
+
+	return heatmap, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestGetAvailabilityHeatmap(t *testing.T) {
+	// Caso de prueba: el usuario 1 ocupado de 09:30 a 10:29 y el usuario 2 hasta las 07:59 del lunes, con intervalos de una hora
+	newSchedules := func() []models.ScheduleModel {
+		return []models.ScheduleModel{
+			{ID: "1", Monday: []models.TimeBlock{{StartMinute: 570, EndMinute: 629}}},
+			{ID: "2", Monday: []models.TimeBlock{{StartMinute: 0, EndMinute: 479}}},
+		}
+	}
+	options := models.PlannerOptions{Heatmap: &models.HeatmapOptions{Resolution: 60, IncludeAttendees: true}}
+
+	expectedCounts := make([]int, 24)
+	expectedAttendees := make([][]string, 24)
+	for hour := range expectedCounts {
+		switch {
+		case hour < 8:
+			expectedCounts[hour], expectedAttendees[hour] = 1, []string{"1"}
+		case hour == 9 || hour == 10:
+			// El usuario 1 no está libre durante toda la hora
+			expectedCounts[hour], expectedAttendees[hour] = 1, []string{"2"}
+		default:
+			expectedCounts[hour], expectedAttendees[hour] = 2, []string{"1", "2"}
+		}
+	}
+	expectedMonday := models.HeatmapDay{DayOfWeek: "l", Resolution: 60, Counts: expectedCounts, Attendees: expectedAttendees}
+
+	heatmap, err := GetAvailabilityHeatmap(context.Background(), newSchedules(), options)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	if len(heatmap) != 7 {
+		t.Fatalf("ERROR Expected 7 days, Got: %d", len(heatmap))
+	}
+	if !reflect.DeepEqual(heatmap[0], expectedMonday) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedMonday, heatmap[0])
+	}
+
+	// Caso de prueba: la resolución por defecto divide el martes libre en 96 intervalos, sin los IDs de los usuarios
+	options.Heatmap = &models.HeatmapOptions{}
+	heatmap, err = GetAvailabilityHeatmap(context.Background(), newSchedules(), options)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	tuesday := heatmap[1]
+	if tuesday.DayOfWeek != "m" || tuesday.Resolution != 15 || len(tuesday.Counts) != 96 || tuesday.Attendees != nil {
+		t.Errorf("ERROR Unexpected tuesday: %v", tuesday)
+	}
+	for _, count := range tuesday.Counts {
+		if count != 2 {
+			t.Errorf("ERROR Expected 2 users in every interval, Got: %v", tuesday.Counts)
+			break
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"Planner/models"
+)
+
+// ConvertHalfOpenRequest convierte una solicitud con intervalos semiabiertos al formato de intervalos cerrados que
+// usan los servicios, restando un minuto a la hora de fin de cada evento y de cada ventana del horario laboral
+func ConvertHalfOpenRequest(request models.PlannerRequest) (models.PlannerRequest, error) {
+	var err error
+
+	// Crear mapas nuevos para no modificar la solicitud original
+	users := request.Users
+	request.Users = make(map[string][]models.Event, len(users))
+	for userID, events := range users {
+		if request.Users[userID], err = convertHalfOpenEvents(events); err != nil {
+			return request, err
+		}
+	}
+
+	if available := request.Available; available != nil {
+		request.Available = make(map[string][]models.Event, len(available))
+		for userID, windows := range available {
+			if request.Available[userID], err = convertHalfOpenEvents(windows); err != nil {
+				return request, err
+			}
+		}
+	}
+
+	if request.WorkingHours.Default, err = convertHalfOpenEvents(request.WorkingHours.Default); err != nil {
+		return request, err
+	}
+
+	workingHoursUsers := request.WorkingHours.Users
+	request.WorkingHours.Users = make(map[string][]models.Event, len(workingHoursUsers))
+	for userID, windows := range workingHoursUsers {
+		if request.WorkingHours.Users[userID], err = convertHalfOpenEvents(windows); err != nil {
+			return request, err
+		}
+	}
+
+	// Convertir también las horas a evitar del modo de recomendaciones, sin modificar las originales
+	if request.Options.Rank != nil && request.Options.Rank.AvoidTimes != nil {
+		rank := *request.Options.Rank
+		rank.AvoidTimes = make([]models.TimeRange, len(request.Options.Rank.AvoidTimes))
+		for i, avoidTime := range request.Options.Rank.AvoidTimes {
+			events, err := convertHalfOpenEvents([]models.Event{{StartTime: avoidTime.StartTime, EndTime: avoidTime.EndTime}})
+			if err != nil {
+				return request, err
+			}
+			rank.AvoidTimes[i] = models.TimeRange{StartTime: events[0].StartTime, EndTime: events[0].EndTime}
+		}
+		request.Options.Rank = &rank
+	}
+
+	return request, nil
+}
+
+// ConvertToHalfOpenEvents convierte los PlannerEvents de la respuesta a intervalos semiabiertos, sumando un minuto
+// a la hora de fin de cada uno. Un slot que termina a la medianoche termina a las "24:00" del mismo día
+func ConvertToHalfOpenEvents(events []models.PlannerEvent) ([]models.PlannerEvent, error) {
+	halfOpenEvents := make([]models.PlannerEvent, len(events))
+	for i, event := range events {
+		endMinute, err := convertToMinutes(strings.Replace(event.EndTime, ":", "", 1))
+		if err != nil {
+			return nil, err
+		}
+
+		event.EndTime = convertToTimeString(endMinute + 1)
+
+		// Los eventos que explican las ausencias también vuelven a tener intervalos semiabiertos
+		if event.Absentees, err = convertToHalfOpenAbsentees(event.Absentees); err != nil {
+			return nil, err
+		}
+		halfOpenEvents[i] = event
+	}
+	return halfOpenEvents, nil
+}
+
+// Función para convertir a intervalos semiabiertos los eventos que explican las ausencias de un slot, sin modificar
+// los originales que comparten los demás slots. Un evento que termina en el último minuto del día termina a las "2400"
+func convertToHalfOpenAbsentees(absentees []models.Absentee) ([]models.Absentee, error) {
+	if absentees == nil {
+		return nil, nil
+	}
+
+	convertedAbsentees := make([]models.Absentee, len(absentees))
+	for i, absentee := range absentees {
+		conflicts := make([]models.BlockSource, len(absentee.Conflicts))
+		for j, conflict := range absentee.Conflicts {
+			if conflict.Event != nil {
+				event := *conflict.Event
+				endMinute, err := convertToMinutes(event.EndTime)
+				if err != nil {
+					return nil, err
+				}
+				event.EndTime = fmt.Sprintf("%02d%02d", (endMinute+1)/60, (endMinute+1)%60)
+				conflict.Event = &event
+			}
+			conflicts[j] = conflict
+		}
+		absentee.Conflicts = conflicts
+		convertedAbsentees[i] = absentee
+	}
+	return convertedAbsentees, nil
+}
+
+// Función para convertir la hora de fin de cada evento de una lista al último minuto que ocupa el evento. Un fin
+// a las "0000" o a las "2400" corresponde al último minuto del día
+func convertHalfOpenEvents(events []models.Event) ([]models.Event, error) {
+	if events == nil {
+		return nil, nil
+	}
+
+	convertedEvents := make([]models.Event, len(events))
+	for i, event := range events {
+		endMinute, err := convertToMinutes(event.EndTime)
+		if err != nil {
+			return nil, err
+		}
+
+		lastMinute := (endMinute - 1 + 1440) % 1440
+		event.EndTime = fmt.Sprintf("%02d%02d", lastMinute/60, lastMinute%60)
+		convertedEvents[i] = event
+	}
+	return convertedEvents, nil
+}
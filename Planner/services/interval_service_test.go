@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestConvertHalfOpenRequest(t *testing.T) {
+	// Caso de prueba: eventos consecutivos, un evento hasta las 24:00 y uno que termina a la medianoche del día siguiente
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {
+				{DayOfWeek: "l", StartTime: "0800", EndTime: "0900"},
+				{DayOfWeek: "l", StartTime: "0900", EndTime: "1000"},
+				{DayOfWeek: "m", StartTime: "2200", EndTime: "2400"},
+				{DayOfWeek: "i", StartTime: "2200", EndTime: "0000"},
+			},
+		},
+		WorkingHours: models.WorkingHours{
+			Default: []models.Event{{DayOfWeek: "l", StartTime: "0700", EndTime: "2100"}},
+		},
+	}
+	expectedRequest := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {
+				{DayOfWeek: "l", StartTime: "0800", EndTime: "0859"},
+				{DayOfWeek: "l", StartTime: "0900", EndTime: "0959"},
+				{DayOfWeek: "m", StartTime: "2200", EndTime: "2359"},
+				{DayOfWeek: "i", StartTime: "2200", EndTime: "2359"},
+			},
+		},
+		WorkingHours: models.WorkingHours{
+			Default: []models.Event{{DayOfWeek: "l", StartTime: "0700", EndTime: "2059"}},
+			Users:   map[string][]models.Event{},
+		},
+	}
+
+	actualRequest, err := ConvertHalfOpenRequest(request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(actualRequest, expectedRequest) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedRequest, actualRequest)
+	}
+
+	// La solicitud original no se modifica
+	if request.Users["1"][0].EndTime != "0900" {
+		t.Errorf("ERROR Expected the original request to keep its end times, Got: %v", request.Users["1"])
+	}
+}
+
+func TestGetAvailableTimeSlotsWithHalfOpenIntervals(t *testing.T) {
+	// Caso de prueba: dos eventos consecutivos forman un solo bloque ocupado y el día termina a las 24:00
+	request, err := ConvertHalfOpenRequest(models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {
+				{DayOfWeek: "l", StartTime: "0800", EndTime: "0900"},
+				{DayOfWeek: "l", StartTime: "0900", EndTime: "1000"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	var mondaySlots []models.PlannerEvent
+	for _, slot := range mustGetAvailableTimeSlots(t, schedules, request.Options) {
+		if slot.DayOfWeek == "l" {
+			mondaySlots = append(mondaySlots, slot)
+		}
+	}
+	actualSlots, err := ConvertToHalfOpenEvents(mondaySlots)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	expectedSlots := []models.PlannerEvent{
+		{DayOfWeek: "l", StartTime: "00:00", EndTime: "08:00", UsersAvailable: 1, Attendees: []string{"1"}, Duration: 480},
+		{DayOfWeek: "l", StartTime: "08:00", EndTime: "10:00", UsersAvailable: 0, Attendees: []string{}, Duration: 120},
+		{DayOfWeek: "l", StartTime: "10:00", EndTime: "24:00", UsersAvailable: 1, Attendees: []string{"1"}, Duration: 840},
+	}
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+}
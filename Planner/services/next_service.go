@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"Planner/models"
+)
+
+// Número de slots a retornar por defecto
+const defaultNextCount = 1
+
+// FindNextSlots obtiene los primeros slots desde el momento options.At en los que al menos minAttendees usuarios
+// (y todos los obligatorios) están libres durante options.Duration minutos. En la semana genérica la búsqueda
+// avanza hasta dar la vuelta completa a la semana; con un rango de fechas, termina en su última fecha. Cada slot
+// inicia en el primer minuto disponible de su ventana y termina donde la ventana termina
+func FindNextSlots(ctx context.Context, schedules []models.ScheduleModel, options models.PlannerOptions) ([]models.PlannerEvent, error) {
+	at, err := convertToMinutes(options.At.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unir todos los días en una sola línea de tiempo, que es circular en la semana genérica
+	options.WeekWrap = true
+	days, segmentsByDay, err := sweepSchedules(ctx, schedules, options)
+	if err != nil {
+		return nil, err
+	}
+	timelines, circular := getTimelines(days, segmentsByDay, options)
+
+	// Expresar el momento de la consulta en minutos desde la medianoche del primer día de la línea de tiempo
+	day := options.At.DayOfWeek
+	if options.At.Date != "" {
+		day = options.At.Date
+	}
+	for i, d := range days {
+		if d == day {
+			at += i * 1440
+		}
+	}
+
+	requiredUsers := getRequiredUsers(schedules)
+	minAttendees := getMinAttendees(options, requiredUsers, len(schedules))
+	windows, err := findMeetingWindows(ctx, timelines[0], minAttendees, options.Duration, options.Granularity, circular)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buscar el primer inicio posible de la reunión en cada ventana, descartando las que no tienen a todos los obligatorios
+	var required userSet
+	if len(windows) > 0 {
+		required = newUserSetOf(windows[0].attendees.ids, requiredUsers)
+	}
+	var candidates []availabilitySegment
+	for _, window := range windows {
+		if !required.isSubsetOf(window.attendees) {
+			continue
+		}
+		if candidate, ok := trimWindowFrom(window, at, options.Duration, options.Granularity, circular); ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	// Ordenar los slots por su inicio y, si empatan, primero los que tienen más asistentes
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].start != candidates[j].start {
+			return candidates[i].start < candidates[j].start
+		}
+		return candidates[i].attendees.count() > candidates[j].attendees.count()
+	})
+
+	count := defaultNextCount
+	if options.Next != nil && options.Next.Count > 0 {
+		count = options.Next.Count
+	}
+	if len(candidates) > count {
+		candidates = candidates[:count]
+	}
+
+	isRequired := make(map[string]bool, len(requiredUsers))
+	for _, userID := range requiredUsers {
+		isRequired[userID] = true
+	}
+	slots := make([]models.PlannerEvent, 0, len(candidates))
+	for _, candidate := range candidates {
+		slot := newWindowEvent(candidate, options.Duration, options.Granularity)
+		if len(requiredUsers) > 0 {
+			slot = splitAttendeeRoles(slot, isRequired)
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// Función auxiliar para recortar una ventana para que inicie en el primer minuto desde at, alineado a la cuadrícula,
+// en el que cabe una reunión de duration minutos. En una línea de tiempo circular también se prueban la ventana de
+// la semana anterior, que puede continuar después de at, y la de la semana siguiente. Retorna false si la reunión
+// no cabe en la ventana después de at
+func trimWindowFrom(window availabilitySegment, at int, duration int, granularity int, circular bool) (availabilitySegment, bool) {
+	shifts := []int{0}
+	if circular {
+		shifts = []int{-7 * 1440, 0, 7 * 1440}
+	}
+
+	// Alinear el momento de la consulta a la cuadrícula, ya que las ventanas ya están alineadas
+	if granularity > 0 {
+		at = -floorDiv(-at, granularity) * granularity
+	}
+
+	for _, shift := range shifts {
+		start, end := window.start+shift, window.end+shift
+		if max(start, at)+duration <= end {
+			window.start, window.end = max(start, at), end
+			return window, true
+		}
+	}
+	return window, false
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestFindNextSlots(t *testing.T) {
+	// Caso de prueba: el usuario 1 está libre el lunes de 13:00 a 15:00, el miércoles de 10:00 a 12:00 y del
+	// domingo a las 23:00 al lunes a la 01:00, y el usuario 2 toda la semana
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	newSchedules := func() []models.ScheduleModel {
+		return []models.ScheduleModel{
+			{
+				ID:        "1",
+				Monday:    []models.TimeBlock{{StartMinute: 60, EndMinute: 779}, {StartMinute: 900, EndMinute: 1439}},
+				Tuesday:   busyDay,
+				Wednesday: []models.TimeBlock{{StartMinute: 0, EndMinute: 599}, {StartMinute: 720, EndMinute: 1439}},
+				Thursday:  busyDay,
+				Friday:    busyDay,
+				Saturday:  busyDay,
+				Sunday:    []models.TimeBlock{{StartMinute: 0, EndMinute: 1379}},
+			},
+			{ID: "2"},
+		}
+	}
+
+	// Desde el lunes a las 14:30 ya no cabe una hora el lunes, así que la búsqueda sigue hasta el lunes siguiente
+	options := models.PlannerOptions{Duration: 60, At: &models.AtOptions{DayOfWeek: "l", Time: "1430"}, Next: &models.NextOptions{Count: 3}}
+	assertNextSlots(t, newSchedules(), options, []string{"i 10:00-11:59", "d 23:00-00:59", "l 13:00-14:59"})
+
+	// Desde el lunes a las 13:20 en una cuadrícula de 15 minutos, el slot inicia a las 13:30
+	options = models.PlannerOptions{Duration: 60, Granularity: 15, At: &models.AtOptions{DayOfWeek: "l", Time: "1320"}}
+	assertNextSlots(t, newSchedules(), options, []string{"l 13:30-14:59"})
+
+	// Desde el domingo a las 23:30 cabe una hora antes de la 01:00 del lunes, pero desde el lunes a las 00:10 ya no
+	options.At = &models.AtOptions{DayOfWeek: "d", Time: "2330"}
+	assertNextSlots(t, newSchedules(), options, []string{"d 23:30-00:59"})
+	options.At = &models.AtOptions{DayOfWeek: "l", Time: "0010"}
+	assertNextSlots(t, newSchedules(), options, []string{"l 13:00-14:59"})
+
+	// Con el usuario 1 obligatorio y un solo asistente, el usuario 2 no alcanza por sí solo
+	schedules := newSchedules()
+	schedules[0].Required = true
+	options = models.PlannerOptions{Duration: 60, MinAttendees: 1, At: &models.AtOptions{DayOfWeek: "m", Time: "0000"}}
+	assertNextSlots(t, schedules, options, []string{"i 10:00-11:59"})
+}
+
+func TestFindNextSlotsWithDates(t *testing.T) {
+	// Caso de prueba: el usuario está libre el 4 de marzo de 10:00 a 11:00 y el 5 de marzo de 08:00 a 10:00
+	newSchedules := func() []models.ScheduleModel {
+		return []models.ScheduleModel{{
+			ID: "1",
+			Dates: map[string][]models.TimeBlock{
+				"2024-03-04": {{StartMinute: 0, EndMinute: 599}, {StartMinute: 660, EndMinute: 1439}},
+				"2024-03-05": {{StartMinute: 0, EndMinute: 479}, {StartMinute: 600, EndMinute: 1439}},
+			},
+		}}
+	}
+	options := models.PlannerOptions{
+		Duration:  60,
+		DateRange: &models.DateRange{From: "2024-03-04", To: "2024-03-05"},
+		At:        &models.AtOptions{Date: "2024-03-04", Time: "1030"},
+	}
+	assertNextSlots(t, newSchedules(), options, []string{"m 08:00-09:59"})
+
+	// La búsqueda no da la vuelta desde la última fecha del rango
+	options.At = &models.AtOptions{Date: "2024-03-05", Time: "0930"}
+	assertNextSlots(t, newSchedules(), options, []string{})
+}
+
+// Función auxiliar para aserciones de los próximos slots, descritos como "día inicio-fin"
+func assertNextSlots(t *testing.T, schedules []models.ScheduleModel, options models.PlannerOptions, expectedSlots []string) {
+	slots, err := FindNextSlots(context.Background(), schedules, options)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	actualSlots := []string{}
+	for _, slot := range slots {
+		actualSlots = append(actualSlots, fmt.Sprintf("%s %s-%s", slot.DayOfWeek, slot.StartTime, slot.EndTime))
+	}
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"Planner/models"
+)
+
+// Función para ordenar los slots según el criterio sortBy. Por hora ("time"), los slots van del lunes al domingo
+// (o por fecha) y luego por hora de inicio. Por asistencia ("attendance") van primero los slots con más asistentes
+// y por duración ("duration") los más largos, desempatando por hora en ambos casos. Cualquier empate restante se
+// resuelve con la duración, los asistentes y sus IDs, por lo que el orden no depende del orden de entrada
+func sortSlots(slots []models.PlannerEvent, sortBy string) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		a, b := slots[i], slots[j]
+		switch sortBy {
+		case models.SortByAttendance:
+			if a.UsersAvailable != b.UsersAvailable {
+				return a.UsersAvailable > b.UsersAvailable
+			}
+		case models.SortByDuration:
+			if a.Duration != b.Duration {
+				return a.Duration > b.Duration
+			}
+		}
+		return compareSlotsByTime(a, b) < 0
+	})
+}
+
+// Función auxiliar para comparar dos slots por fecha, día de la semana y hora de inicio. Los slots que inician al
+// mismo tiempo se comparan por duración (primero los más cortos), cantidad de asistentes (primero los que tienen
+// más) y finalmente por sus IDs. Retorna un número negativo si a va antes que b, cero si son iguales y positivo si no
+func compareSlotsByTime(a, b models.PlannerEvent) int {
+	switch {
+	case a.Date != b.Date:
+		return strings.Compare(a.Date, b.Date)
+	case a.DayOfWeek != b.DayOfWeek:
+		return dayIndex(a.DayOfWeek) - dayIndex(b.DayOfWeek)
+	case a.StartTime != b.StartTime:
+		return strings.Compare(a.StartTime, b.StartTime)
+	case a.Duration != b.Duration:
+		return a.Duration - b.Duration
+	case a.UsersAvailable != b.UsersAvailable:
+		return b.UsersAvailable - a.UsersAvailable
+	}
+	return strings.Compare(strings.Join(a.Attendees, ","), strings.Join(b.Attendees, ","))
+}
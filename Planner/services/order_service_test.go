@@ -0,0 +1,96 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestGetAvailableTimeSlotsOrdering(t *testing.T) {
+	// Caso de prueba: los usuarios 1 y 2 coinciden el lunes de 10:00 a 11:00, el usuario 1 también está libre el
+	// martes de 08:00 a 12:00 y el usuario 2 el lunes de 07:00 a 08:00 y el domingo de 14:00 a 14:30
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	newSchedules := func() []models.ScheduleModel {
+		return []models.ScheduleModel{
+			{
+				ID:        "1",
+				Monday:    []models.TimeBlock{{StartMinute: 0, EndMinute: 599}, {StartMinute: 660, EndMinute: 1439}},
+				Tuesday:   []models.TimeBlock{{StartMinute: 0, EndMinute: 479}, {StartMinute: 720, EndMinute: 1439}},
+				Wednesday: busyDay, Thursday: busyDay, Friday: busyDay, Saturday: busyDay, Sunday: busyDay,
+			},
+			{
+				ID:      "2",
+				Monday:  []models.TimeBlock{{StartMinute: 0, EndMinute: 419}, {StartMinute: 480, EndMinute: 599}, {StartMinute: 660, EndMinute: 1439}},
+				Tuesday: busyDay, Wednesday: busyDay, Thursday: busyDay, Friday: busyDay, Saturday: busyDay,
+				Sunday: []models.TimeBlock{{StartMinute: 0, EndMinute: 839}, {StartMinute: 870, EndMinute: 1439}},
+			},
+		}
+	}
+	options := models.PlannerOptions{Duration: 30, MinAttendees: 1}
+
+	// Sin un criterio, los slots van del lunes al domingo y luego por hora de inicio
+	assertSlotOrder(t, newSchedules, options, []string{"l 07:00 [2]", "l 10:00 [1 2]", "m 08:00 [1]", "d 14:00 [2]"})
+
+	options.SortBy = models.SortByTime
+	assertSlotOrder(t, newSchedules, options, []string{"l 07:00 [2]", "l 10:00 [1 2]", "m 08:00 [1]", "d 14:00 [2]"})
+
+	// Por asistencia, primero el slot con los dos usuarios y luego el resto por hora
+	options.SortBy = models.SortByAttendance
+	assertSlotOrder(t, newSchedules, options, []string{"l 10:00 [1 2]", "l 07:00 [2]", "m 08:00 [1]", "d 14:00 [2]"})
+
+	// Por duración, primero el martes de cuatro horas y los slots de una hora por hora
+	options.SortBy = models.SortByDuration
+	assertSlotOrder(t, newSchedules, options, []string{"m 08:00 [1]", "l 07:00 [2]", "l 10:00 [1 2]", "d 14:00 [2]"})
+}
+
+func TestCompareSlotsByTime(t *testing.T) {
+	// Caso de prueba: los slots que inician al mismo tiempo se ordenan por duración, asistentes e IDs, y los días
+	// del lunes al domingo
+	slots := []models.PlannerEvent{
+		{DayOfWeek: "l", StartTime: "09:00", Duration: 120, UsersAvailable: 1, Attendees: []string{"1"}},
+		{DayOfWeek: "l", StartTime: "09:00", Duration: 60, UsersAvailable: 1, Attendees: []string{"2"}},
+		{DayOfWeek: "l", StartTime: "09:00", Duration: 60, UsersAvailable: 1, Attendees: []string{"1"}},
+		{DayOfWeek: "l", StartTime: "09:00", Duration: 60, UsersAvailable: 2, Attendees: []string{"1", "2"}},
+		{DayOfWeek: "d", StartTime: "08:00", Duration: 60, UsersAvailable: 2, Attendees: []string{"1", "2"}},
+		{DayOfWeek: "m", StartTime: "08:00", Duration: 60, UsersAvailable: 2, Attendees: []string{"1", "2"}},
+	}
+	expectedOrder := []int{3, 2, 1, 0, 5, 4}
+
+	sorted := append([]models.PlannerEvent{}, slots...)
+	sortSlots(sorted, models.SortByTime)
+	var expectedSlots []models.PlannerEvent
+	for _, i := range expectedOrder {
+		expectedSlots = append(expectedSlots, slots[i])
+	}
+	if !reflect.DeepEqual(sorted, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, sorted)
+	}
+}
+
+// Función auxiliar para aserciones del orden de los slots, repitiendo el cálculo para detectar un orden inestable
+func assertSlotOrder(t *testing.T, newSchedules func() []models.ScheduleModel, options models.PlannerOptions, expectedOrder []string) {
+	for run := 0; run < 20; run++ {
+		actualOrder := []string{}
+		for _, slot := range mustGetAvailableTimeSlots(t, newSchedules(), options) {
+			actualOrder = append(actualOrder, fmt.Sprintf("%s %s %v", slot.DayOfWeek, slot.StartTime, slot.Attendees))
+		}
+		if !reflect.DeepEqual(actualOrder, expectedOrder) {
+			t.Errorf("ERROR Expected: %v, Got: %v", expectedOrder, actualOrder)
+			return
+		}
+	}
+}
+
+func TestSortSlotsByDate(t *testing.T) {
+	// Caso de prueba: en un rango de fechas, el domingo 3 de marzo va antes que el lunes 4
+	slots := []models.PlannerEvent{
+		{DayOfWeek: "l", Date: "2024-03-04", StartTime: "08:00", Duration: 60},
+		{DayOfWeek: "d", Date: "2024-03-03", StartTime: "09:00", Duration: 60},
+	}
+	sortSlots(slots, models.SortByTime)
+	if slots[0].Date != "2024-03-03" || slots[1].Date != "2024-03-04" {
+		t.Errorf("ERROR Expected: %v, Got: %v", []string{"2024-03-03", "2024-03-04"}, []string{slots[0].Date, slots[1].Date})
+	}
+}
@@ -2,10 +2,16 @@ package services
 
 import (
 	"Planner/models"
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 )
 
+// Días de la semana en orden, iniciando el lunes
+var weekDays = []string{"l", "m", "i", "j", "v", "s", "d"}
+
 // Función para normalizar el horario de un usuario
 func normalizeSchedule(schedule models.ScheduleModel) models.ScheduleModel {
 	// Copiar los TimeBlocks de cada día de la semana a un mapa para realizar la fusión
@@ -18,14 +24,21 @@ func normalizeSchedule(schedule models.ScheduleModel) models.ScheduleModel {
 	timeBlocksMap["s"] = schedule.Saturday
 	timeBlocksMap["d"] = schedule.Sunday
 
+	// Agregar el margen de tiempo antes y después de cada evento y trasladar a los días vecinos la parte de los
+	// TimeBlocks que cruza la medianoche. El margen se consume aquí, por lo que no se copia al horario normalizado,
+	// y tampoco se distingue el tiempo fuera de las ventanas del usuario
+	timeBlocksMap = padBlocks(timeBlocksMap, schedule.BufferBefore, schedule.BufferAfter)
+	timeBlocksMap = splitBlocksAtMidnight(timeBlocksMap, weekDays, true)
+
 	// Fusionar los TimeBlocks de cada día de la semana
-	for _, day := range []string{"l", "m", "i", "j", "v", "s", "d"} {
+	for _, day := range weekDays {
 		timeBlocksMap[day] = mergeBlocks(timeBlocksMap[day])
 	}
 
 	// Actualizar el horario del usuario con los TimeBlocks fusionados
 	normalizedSchedule := models.ScheduleModel{
 		ID:        schedule.ID,
+		Required:  schedule.Required,
 		Monday:    timeBlocksMap["l"],
 		Tuesday:   timeBlocksMap["m"],
 		Wednesday: timeBlocksMap["i"],
@@ -35,11 +48,121 @@ func normalizeSchedule(schedule models.ScheduleModel) models.ScheduleModel {
 		Sunday:    timeBlocksMap["d"],
 	}
 
+	// Repetir el proceso con los TimeBlocks de cada fecha, sin dar la vuelta desde la última fecha a la primera
+	if schedule.Dates != nil {
+		dates := getScheduleDates([]models.ScheduleModel{schedule})
+		dateBlocksMap := splitBlocksAtMidnight(padBlocks(schedule.Dates, schedule.BufferBefore, schedule.BufferAfter), dates, false)
+
+		normalizedSchedule.Dates = make(map[string][]models.TimeBlock, len(dates))
+		for _, date := range dates {
+			normalizedSchedule.Dates[date] = mergeBlocks(dateBlocksMap[date])
+		}
+	}
+
 	return normalizedSchedule
 }
 
-// Función para fusionar TimeBlocks sin overlapping
+// Función para dividir los TimeBlocks que inician antes de la medianoche de su día (StartMinute < 0) o que
+// terminan después de ella (EndMinute >= 1440), dejando en cada día consecutivo de days la parte que le
+// corresponde. Si los días son circulares (la semana genérica), el domingo continúa en el lunes; si no, las
+// partes fuera de los días se descartan
+func splitBlocksAtMidnight(timeBlocksMap map[string][]models.TimeBlock, days []string, circular bool) map[string][]models.TimeBlock {
+	splitBlocksMap := make(map[string][]models.TimeBlock, len(days))
+	for i, day := range days {
+		for _, block := range timeBlocksMap[day] {
+			// Recorrer cada día que toca el bloque, desde el día de su inicio hasta el de su fin
+			for offset := floorDiv(block.StartMinute, 1440); offset <= floorDiv(block.EndMinute, 1440); offset++ {
+				j := i + offset
+				if circular {
+					j = ((j % len(days)) + len(days)) % len(days)
+				} else if j < 0 || j >= len(days) {
+					continue
+				}
+
+				piece := models.TimeBlock{
+					StartMinute: max(block.StartMinute-offset*1440, 0),
+					EndMinute:   min(block.EndMinute-offset*1440, 1440-1),
+					Tentative:   block.Tentative,
+					Sources:     block.Sources,
+				}
+				splitBlocksMap[days[j]] = append(splitBlocksMap[days[j]], piece)
+			}
+		}
+	}
+	return splitBlocksMap
+}
+
+// Función para agregar un margen de tiempo antes y después de cada TimeBlock, sin modificar los originales. El
+// tiempo fuera de las ventanas del usuario no recibe margen, ya que son los límites que declara y no eventos
+func padBlocks(timeBlocksMap map[string][]models.TimeBlock, before int, after int) map[string][]models.TimeBlock {
+	if before == 0 && after == 0 {
+		return timeBlocksMap
+	}
+
+	paddedBlocksMap := make(map[string][]models.TimeBlock, len(timeBlocksMap))
+	for day, timeBlocks := range timeBlocksMap {
+		paddedBlocks := make([]models.TimeBlock, len(timeBlocks))
+		for i, block := range timeBlocks {
+			if block.Outside {
+				paddedBlocks[i] = block
+				continue
+			}
+			paddedBlocks[i] = models.TimeBlock{StartMinute: block.StartMinute - before, EndMinute: block.EndMinute + after, Tentative: block.Tentative, Sources: block.Sources}
+		}
+		paddedBlocksMap[day] = paddedBlocks
+	}
+	return paddedBlocksMap
+}
+
+// Función para alinear un segmento a una cuadrícula de granularity minutos, reduciéndolo hacia adentro: el inicio
+// se redondea hacia arriba y el fin hacia abajo. Retorna false si no queda ningún minuto de la cuadrícula
+func snapSegment(segment availabilitySegment, granularity int) (availabilitySegment, bool) {
+	if granularity <= 0 {
+		return segment, true
+	}
+
+	// Los días tienen un número entero de intervalos de la cuadrícula, por lo que basta con alinear los minutos
+	segment.start = -floorDiv(-segment.start, granularity) * granularity
+	segment.end = floorDiv(segment.end, granularity) * granularity
+	return segment, segment.end > segment.start
+}
+
+// Función auxiliar para la división entera que redondea hacia abajo también con números negativos
+func floorDiv(a, b int) int {
+	if a < 0 && a%b != 0 {
+		return a/b - 1
+	}
+	return a / b
+}
+
+// Función para fusionar TimeBlocks sin overlapping. Los bloques ocupados y los tentativos se fusionan por separado
+// y, donde se solapan, prevalece el bloque ocupado
 func mergeBlocks(timeBlocks []models.TimeBlock) []models.TimeBlock {
+	// Separar los bloques tentativos de los ocupados
+	var busyBlocks, tentativeBlocks []models.TimeBlock
+	for _, block := range timeBlocks {
+		if block.Tentative {
+			tentativeBlocks = append(tentativeBlocks, block)
+		} else {
+			busyBlocks = append(busyBlocks, block)
+		}
+	}
+	if len(tentativeBlocks) == 0 {
+		return mergeBlocksOfSameLevel(timeBlocks)
+	}
+
+	// Los bloques tentativos solo conservan los minutos que no están ocupados
+	mergedBlocks := mergeBlocksOfSameLevel(busyBlocks)
+	mergedBlocks = append(mergedBlocks, subtractBlocks(mergeBlocksOfSameLevel(tentativeBlocks), mergedBlocks)...)
+	sort.Slice(mergedBlocks, func(i, j int) bool {
+		return mergedBlocks[i].StartMinute < mergedBlocks[j].StartMinute
+	})
+
+	return mergedBlocks
+}
+
+// Función para fusionar TimeBlocks del mismo nivel de ocupación
+func mergeBlocksOfSameLevel(timeBlocks []models.TimeBlock) []models.TimeBlock {
 	// Caso que no tiene elementos
 	if len(timeBlocks) < 1 {
 		return timeBlocks
@@ -50,12 +173,13 @@ func mergeBlocks(timeBlocks []models.TimeBlock) []models.TimeBlock {
 		return timeBlocks[i].StartMinute < timeBlocks[j].StartMinute
 	})
 
-	// Fusionar TimeBlocks sin overlapping
+	// Fusionar TimeBlocks que se solapan o que son contiguos (los minutos de fin son inclusivos)
 	mergedBlocks := []models.TimeBlock{timeBlocks[0]}
 	for _, block := range timeBlocks[1:] {
-		lastAdded := mergedBlocks[len(mergedBlocks)-1]
-		if block.StartMinute < lastAdded.EndMinute {
+		lastAdded := &mergedBlocks[len(mergedBlocks)-1]
+		if block.StartMinute <= lastAdded.EndMinute+1 {
 			lastAdded.EndMinute = max(lastAdded.EndMinute, block.EndMinute)
+			lastAdded.Sources = mergeSources(lastAdded.Sources, block.Sources)
 		} else {
 			mergedBlocks = append(mergedBlocks, block)
 		}
@@ -64,6 +188,21 @@ func mergeBlocks(timeBlocks []models.TimeBlock) []models.TimeBlock {
 	return mergedBlocks
 }
 
+// Función auxiliar para unir los orígenes de dos bloques fusionados sin repetirlos. Crea un arreglo nuevo porque
+// los bloques divididos o copiados comparten el arreglo de orígenes
+func mergeSources(sources []*models.BlockSource, other []*models.BlockSource) []*models.BlockSource {
+	if len(other) == 0 {
+		return sources
+	}
+	merged := append([]*models.BlockSource{}, sources...)
+	for _, source := range other {
+		if !slices.Contains(merged, source) {
+			merged = append(merged, source)
+		}
+	}
+	return merged
+}
+
 // Función para unir los time blocks de todos los usuarios en un único array por día de la semana
 func mergeTimeBlocksByDay(schedules []models.ScheduleModel) map[string][]models.UserTimeBlock {
 	mergedTimeBlocks := make(map[string][]models.UserTimeBlock)
@@ -71,7 +210,7 @@ func mergeTimeBlocksByDay(schedules []models.ScheduleModel) map[string][]models.
 	for _, schedule := range schedules {
 
 		// Unir los time blocks por día de la semana
-		for _, day := range []string{"l", "m", "i", "j", "v", "s", "d"} {
+		for _, day := range weekDays {
 			switch day {
 			case "l":
 				mergedTimeBlocks["l"] = append(mergedTimeBlocks["l"], convertToUserTimeBlocks(schedule.Monday, schedule.ID)...)
@@ -94,6 +233,18 @@ func mergeTimeBlocksByDay(schedules []models.ScheduleModel) map[string][]models.
 	return mergedTimeBlocks
 }
 
+// Función para unir los time blocks de todos los usuarios en un único array por fecha
+func mergeTimeBlocksByDate(schedules []models.ScheduleModel, dates []string) map[string][]models.UserTimeBlock {
+	mergedTimeBlocks := make(map[string][]models.UserTimeBlock, len(dates))
+	for _, date := range dates {
+		mergedTimeBlocks[date] = []models.UserTimeBlock{}
+		for _, schedule := range schedules {
+			mergedTimeBlocks[date] = append(mergedTimeBlocks[date], convertToUserTimeBlocks(schedule.Dates[date], schedule.ID)...)
+		}
+	}
+	return mergedTimeBlocks
+}
+
 // Función auxiliar para convertir TimeBlock a UserTimeBlock con el ID de usuario especificado
 func convertToUserTimeBlocks(timeBlocks []models.TimeBlock, userID string) []models.UserTimeBlock {
 	userTimeBlocks := make([]models.UserTimeBlock, len(timeBlocks))
@@ -108,102 +259,205 @@ func convertToUserTimeBlocks(timeBlocks []models.TimeBlock, userID string) []mod
 
 // Struct para representar un punto de tiempo con información de ocupación
 type timePoint struct {
-	time   int    // Tiempo en minutos
-	userID string // ID del usuario
-	isBusy bool   // Indica si el usuario está ocupado (true) o libre (false)
+	time      int  // Tiempo en minutos
+	user      int  // Índice del usuario en la tabla de IDs, o -1 para los límites del día
+	isBusy    bool // Indica si el usuario está ocupado (true) o libre (false)
+	tentative bool // Indica si el bloque que inicia o termina es tentativo
+}
+
+// Struct para representar un segmento de la barrida con los usuarios disponibles. Los minutos se cuentan
+// desde la medianoche del día del segmento y pueden superar 1440 si el segmento continúa en los días siguientes
+type availabilitySegment struct {
+	day       string  // Día de la semana
+	start     int     // Minuto de inicio (inclusive)
+	end       int     // Minuto de fin (exclusivo)
+	attendees userSet // Usuarios disponibles
+	tentative userSet // Usuarios que solo tienen bloques tentativos
 }
 
-// Función para encontrar los time slots disponibles por día
-func findAvailableTimeSlotsByDay(day string, timeBlocks []models.UserTimeBlock, usersId []string) []models.PlannerEvent {
+// Función para obtener la tabla de IDs ordenados y sin repetir sobre la que se construyen los conjuntos de usuarios
+func newUserTable(usersId []string) []string {
+	ids := append([]string{}, usersId...)
+	sort.Strings(ids)
+	return slices.Compact(ids)
+}
+
+// Función para recorrer los bloques de tiempo de un día y obtener los segmentos con los usuarios disponibles.
+// Los IDs de los usuarios se convierten en índices de la tabla ids, por lo que cada actualización de los
+// usuarios disponibles cambia un solo bit en lugar de reordenar una lista
+func sweepAvailabilityByDay(ctx context.Context, day string, timeBlocks []models.UserTimeBlock, ids []string) ([]availabilitySegment, error) {
+	// Mapa para convertir cada ID de usuario en su índice de la tabla
+	userIndex := make(map[string]int, len(ids))
+	for i, userID := range ids {
+		userIndex[userID] = i
+	}
+
 	// Array para almacenar los puntos de tiempo con información de ocupación
-	var timePoints []timePoint
+	timePoints := make([]timePoint, 0, 2*len(timeBlocks)+2)
 
 	// Agregar los timePoints correspondientes a los bloques de tiempo de los usuarios
 	for _, tb := range timeBlocks {
-		timePoints = append(timePoints, timePoint{time: tb.StartMinute, userID: tb.UserID, isBusy: true})
-		timePoints = append(timePoints, timePoint{time: tb.EndMinute + 1, userID: tb.UserID, isBusy: false})
+		user, ok := userIndex[tb.UserID]
+		if !ok {
+			continue
+		}
+		timePoints = append(timePoints, timePoint{time: tb.StartMinute, user: user, isBusy: true, tentative: tb.Tentative})
+		timePoints = append(timePoints, timePoint{time: tb.EndMinute + 1, user: user, isBusy: false, tentative: tb.Tentative})
 	}
 
-	// Ordenar los timePoints en orden ascendente
+	// Ordenar los timePoints en orden ascendente. En un mismo minuto, los fines van antes que los inicios para que
+	// un bloque ocupado seguido de uno tentativo del mismo usuario se procese en orden
 	sort.Slice(timePoints, func(i, j int) bool {
-		return timePoints[i].time < timePoints[j].time
+		if timePoints[i].time != timePoints[j].time {
+			return timePoints[i].time < timePoints[j].time
+		}
+		return !timePoints[i].isBusy && timePoints[j].isBusy
 	})
 
 	// Agregar los tiempos de inicio y fin únicos al array de timePoints
-	timePoints = append([]timePoint{{time: 0, userID: "--BEGIN--"}}, timePoints...) // Tiempo inicial del día
-	timePoints = append(timePoints, timePoint{time: 1440, userID: "--END--"})       // Tiempo final del día
+	timePoints = append([]timePoint{{time: 0, user: -1}}, timePoints...) // Tiempo inicial del día
+	timePoints = append(timePoints, timePoint{time: 1440, user: -1})     // Tiempo final del día
 
-	// Lista para almacenar los eventos planificados
-	var plannerEvents []models.PlannerEvent
+	// Lista para almacenar los segmentos de la barrida
+	var segments []availabilitySegment
 
-	// Lista para rastrear los usuarios disponibles en cada momento
-	availableUsers := make([]string, len(usersId))
-
-	// Inicializar los usuarios disponibles en el tiempo inicial
-	copy(availableUsers, usersId)
-	sort.Strings(availableUsers)
+	// Conjuntos para rastrear los usuarios disponibles y los que solo tienen bloques tentativos en cada momento,
+	// junto con el número de bloques ocupados y tentativos abiertos de cada usuario
+	availableUsers := newFullUserSet(ids)
+	tentativeUsers := newUserSet(ids)
+	busyCount := make([]int, len(ids))
+	tentativeCount := make([]int, len(ids))
 
 	// Iterar sobre cada timePoint
 	for i := 1; i < len(timePoints); i++ {
 
-		// Calcular la duración entre el tiempo previo y el tiempo actual
-		duration := timePoints[i].time - timePoints[i-1].time
-
-		// Si la duración es mayor que cero, significa que hay un intervalo disponible
-		if duration > 0 {
-
-			// Crear un evento de PlannerEvent para este intervalo de tiempo
-			event := models.PlannerEvent{
-				DayOfWeek:      day,
-				StartTime:      convertToTimeString(timePoints[i-1].time),
-				EndTime:        convertToTimeString(timePoints[i].time - 1),
-				UsersAvailable: len(availableUsers),
-				Attendees:      make([]string, len(availableUsers)),
-				Duration:       duration,
+		// Revisar periódicamente si la solicitud fue cancelada
+		if i%contextCheckInterval == 0 {
+			if err := checkContext(ctx); err != nil {
+				return nil, err
 			}
+		}
 
-			// Copiar los usuarios disponibles al evento
-			copy(event.Attendees, availableUsers)
-
-			// Agregar el evento al slice de PlannerEvent
-			plannerEvents = append(plannerEvents, event)
+		// Si la duración es mayor que cero, significa que hay un intervalo disponible
+		if timePoints[i].time > timePoints[i-1].time {
+
+			// Crear un segmento para este intervalo de tiempo con una copia de los usuarios disponibles
+			segments = append(segments, availabilitySegment{
+				day:       day,
+				start:     timePoints[i-1].time,
+				end:       timePoints[i].time,
+				attendees: availableUsers.clone(),
+				tentative: tentativeUsers.clone(),
+			})
 		}
 
-		// Actualizar los usuarios disponibles en este intervalo de tiempo, es un startTime
-		if timePoints[i].isBusy {
-			decrementAvailableUsers(&availableUsers, timePoints[i].userID)
+		user := timePoints[i].user
+		if user < 0 {
+			continue
 		}
 
-		// Actualizar los usuarios disponibles en este intervalo de tiempo, es un endTime
+		// Actualizar los bloques abiertos del usuario: un startTime abre un bloque y un endTime lo cierra
+		delta := 1
 		if !timePoints[i].isBusy {
-			incrementAvailableUsers(&availableUsers, timePoints[i].userID)
+			delta = -1
+		}
+		if timePoints[i].tentative {
+			tentativeCount[user] += delta
+		} else {
+			busyCount[user] += delta
+		}
+
+		// Un usuario está disponible sin bloques abiertos y es tentativo si solo tiene bloques tentativos abiertos
+		availableUsers.remove(user)
+		tentativeUsers.remove(user)
+		if busyCount[user] == 0 && tentativeCount[user] == 0 {
+			availableUsers.add(user)
+		} else if busyCount[user] == 0 {
+			tentativeUsers.add(user)
 		}
 	}
 
-	return plannerEvents
+	return segments, nil
 }
 
-// Función auxiliar para actualizar los usuarios disponibles en cada intervalo de tiempo
-func incrementAvailableUsers(users *[]string, userID string) {
-	if userID == "--END--" || userID == "--BEGIN--" {
-		return
+// Función auxiliar para convertir un segmento en un PlannerEvent
+func newPlannerEvent(segment availabilitySegment) models.PlannerEvent {
+	startDay := shiftDay(segment.day, segment.start/1440)
+	event := models.PlannerEvent{
+		StartTime:      convertToTimeString(segment.start % 1440),
+		EndTime:        convertToTimeString((segment.end - 1) % 1440),
+		UsersAvailable: segment.attendees.count(),
+		Attendees:      segment.attendees.toIDs(),
+		Duration:       segment.end - segment.start,
 	}
-	*users = append(*users, userID)
-	sort.Strings(*users)
+
+	event.DayOfWeek, event.Date = describeDay(startDay)
+
+	// Indicar el día de fin si el segmento termina en un día distinto al de inicio
+	endDay := shiftDay(segment.day, (segment.end-1)/1440)
+	if endDay != startDay || event.Duration > 1440 {
+		event.EndDayOfWeek, event.EndDate = describeDay(endDay)
+	}
+
+	// Agregar los usuarios tentativos al evento
+	if segment.tentative.count() > 0 {
+		event.TentativeAttendees = segment.tentative.toIDs()
+	}
+
+	return event
 }
 
-// Función auxiliar para actualizar los usuarios disponibles en cada intervalo de tiempo
-func decrementAvailableUsers(users *[]string, userID string) {
-	if userID == "--BEGIN--" || userID == "--END--" {
-		return
+// Función para unir los segmentos de días consecutivos en una sola línea de tiempo que inicia el primer día,
+// fusionando los segmentos contiguos con los mismos usuarios disponibles. Si la línea de tiempo es circular
+// (la semana genérica), el último día también continúa en el primero
+func joinDaySegments(segmentsByDay map[string][]availabilitySegment, days []string, circular bool) []availabilitySegment {
+	var segments []availabilitySegment
+	for i, day := range days {
+		for _, segment := range segmentsByDay[day] {
+			// Expresar el segmento en minutos desde la medianoche del primer día
+			segment.day = days[0]
+			segment.start += i * 1440
+			segment.end += i * 1440
+
+			// Extender el segmento anterior si continúa con los mismos usuarios disponibles
+			if last := len(segments) - 1; last >= 0 && segments[last].end == segment.start && equalSegmentUsers(segments[last], segment) {
+				segments[last].end = segment.end
+				continue
+			}
+			segments = append(segments, segment)
+		}
 	}
-	for i := len(*users) - 1; i >= 0; i-- {
-		if (*users)[i] == userID {
-			*users = append((*users)[:i], (*users)[i+1:]...)
-			break
+
+	// Si el último día termina con los mismos usuarios con los que inicia el primero, el último segmento continúa en el primero
+	if last := len(segments) - 1; circular && last > 0 && equalSegmentUsers(segments[last], segments[0]) {
+		segments[last].end = len(days)*1440 + segments[0].end
+		segments = segments[1:]
+	}
+
+	return segments
+}
+
+// Función auxiliar para verificar si dos segmentos tienen los mismos usuarios disponibles y tentativos
+func equalSegmentUsers(a, b availabilitySegment) bool {
+	return a.attendees.equal(b.attendees) && a.tentative.equal(b.tentative)
+}
+
+// Función auxiliar para obtener la posición de un día dentro de la semana, iniciando el lunes
+func dayIndex(day string) int {
+	for i, weekDay := range weekDays {
+		if weekDay == day {
+			return i
 		}
 	}
-	sort.Strings(*users)
+	return -1
+}
+
+// Función auxiliar para obtener el día que está n días después de day, que puede ser un día de la semana o una fecha
+func shiftDay(day string, n int) string {
+	if isDate(day) {
+		return shiftDate(day, n)
+	}
+	return weekDays[((dayIndex(day)+n)%len(weekDays)+len(weekDays))%len(weekDays)]
 }
 
 // Función auxiliar para convertir minutos en formato de cadena de tiempo (HH:MM)
@@ -213,40 +467,191 @@ func convertToTimeString(minutes int) string {
 	return fmt.Sprintf("%02d:%02d", hours, mins)
 }
 
-// GetAvailableTimeSlots simula la obtención de los slots de tiempo disponibles
-func GetAvailableTimeSlots(schedules []models.ScheduleModel) []models.PlannerEvent {
+// TimeoutError indica que el cálculo superó el tiempo límite de la solicitud antes de terminar
+type TimeoutError struct {
+	Err error // Error del contexto que originó el corte
+}
+
+func (e *TimeoutError) Error() string {
+	return "Planner computation exceeded the request deadline"
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Número de iteraciones entre cada revisión del contexto en los ciclos más largos
+const contextCheckInterval = 1024
+
+// Función auxiliar para verificar si la solicitud fue cancelada o superó su tiempo límite. Superar el tiempo
+// límite retorna un TimeoutError, y la cancelación retorna el error del contexto sin modificar
+func checkContext(ctx context.Context) error {
+	err := ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{Err: err}
+	}
+	return err
+}
+
+// GetAvailableTimeSlots obtiene los slots de tiempo disponibles de los usuarios según las opciones de la solicitud.
+// El cálculo se detiene si ctx es cancelado o supera su tiempo límite
+func GetAvailableTimeSlots(ctx context.Context, schedules []models.ScheduleModel, options models.PlannerOptions) ([]models.PlannerEvent, error) {
+	// Conservar los horarios sin normalizar, en los que los eventos no tienen margen y se distinguen del tiempo fuera
+	// de las ventanas de cada usuario
+	rawSchedules := append([]models.ScheduleModel{}, schedules...)
+
+	// Barrer los horarios de todos los usuarios para obtener los segmentos de cada día
+	days, segmentsByDay, err := sweepSchedules(ctx, schedules, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Obtener los IDs de los usuarios obligatorios, si la solicitud los distingue
+	requiredUsers := getRequiredUsers(schedules)
+
+	minAttendees := getMinAttendees(options, requiredUsers, len(schedules))
+
+	// Agrupar los segmentos en líneas de tiempo
+	timelines, circular := getTimelines(days, segmentsByDay, options)
+
+	availableSlotsByDay := make([]models.PlannerEvent, 0)
+	for _, segments := range timelines {
+		// Sin una duración solicitada se retornan todos los segmentos de la barrida
+		if options.Duration <= 0 {
+			for _, segment := range segments {
+				if segment, ok := snapSegment(segment, options.Granularity); ok {
+					availableSlotsByDay = append(availableSlotsByDay, newPlannerEvent(segment))
+				}
+			}
+			continue
+		}
+
+		// Con una duración solicitada se retornan solo las ventanas donde cabe la reunión
+		slots, err := findMeetingSlots(ctx, segments, minAttendees, options.Duration, options.Granularity, circular)
+		if err != nil {
+			return nil, err
+		}
+		availableSlotsByDay = append(availableSlotsByDay, slots...)
+	}
+
+	// Ordenar los slots por hora para que la respuesta no dependa del orden en que se calcularon
+	sortSlots(availableSlotsByDay, models.SortByTime)
+
+	// Descartar los slots sin todos los usuarios obligatorios y ordenar según los opcionales disponibles
+	if len(requiredUsers) > 0 {
+		availableSlotsByDay = applyAttendeeRoles(availableSlotsByDay, requiredUsers)
+	}
+
+	// Ordenar los slots según el criterio de la solicitud, si lo envía
+	if options.SortBy != "" {
+		sortSlots(availableSlotsByDay, options.SortBy)
+	}
+
+	// En el modo de recomendaciones, ordenar los slots por puntaje y conservar los mejores. Los slots con el mismo
+	// puntaje conservan el orden anterior
+	if options.Rank != nil {
+		availableSlotsByDay = rankSlots(availableSlotsByDay, rawSchedules, minAttendees, options.Duration, *options.Rank)
+	}
+
+	// En el modo de explicación, indicar en cada slot los usuarios que no asisten y los eventos que se lo impiden
+	if options.Explain {
+		if err := explainSlots(ctx, availableSlotsByDay, schedules); err != nil {
+			return nil, err
+		}
+	}
+
+	return availableSlotsByDay, nil
+}
+
+// Función auxiliar para obtener el mínimo de asistentes de la solicitud. Si no se especifica, se exige la presencia
+// de todos los usuarios obligatorios, o de todos los usuarios si no hay obligatorios
+func getMinAttendees(options models.PlannerOptions, requiredUsers []string, usersCount int) int {
+	if options.MinAttendees > 0 {
+		return options.MinAttendees
+	}
+	if len(requiredUsers) > 0 {
+		return len(requiredUsers)
+	}
+	return usersCount
+}
+
+// Función para agrupar los segmentos de cada día en líneas de tiempo: una por día, o una sola para todos los días
+// que es circular en la semana genérica y lineal en un rango de fechas
+func getTimelines(days []string, segmentsByDay map[string][]availabilitySegment, options models.PlannerOptions) ([][]availabilitySegment, bool) {
+	circular := options.WeekWrap && options.DateRange == nil
+	if options.WeekWrap {
+		return [][]availabilitySegment{joinDaySegments(segmentsByDay, days, circular)}, circular
+	}
+
+	var timelines [][]availabilitySegment
+	for _, day := range days {
+		if segments, ok := segmentsByDay[day]; ok {
+			timelines = append(timelines, segments)
+		}
+	}
+	return timelines, circular
+}
+
+// Función para normalizar los horarios de los usuarios y barrer sus TimeBlocks, obteniendo los días (o fechas)
+// a planificar y los segmentos de disponibilidad de cada uno
+func sweepSchedules(ctx context.Context, schedules []models.ScheduleModel, options models.PlannerOptions) ([]string, map[string][]availabilitySegment, error) {
 
 	// Normalizar los horarios de todos los usuarios
 	for i := range schedules {
 		schedules[i] = normalizeSchedule(schedules[i])
 	}
 
-	// Obtener los IDs de los usuarios
+	// Obtener la tabla de IDs de los usuarios, compartida por los segmentos de todos los días
 	usersId := make([]string, len(schedules))
 	for i, schedule := range schedules {
 		usersId[i] = schedule.ID
 	}
+	ids := newUserTable(usersId)
 
-	// Unir los time blocks de todos los usuarios por día de la semana
+	// Unir los time blocks de todos los usuarios por día de la semana, o por fecha si se planifica sobre un rango de fechas
+	days := weekDays
 	mergedTimeBlocksByDay := mergeTimeBlocksByDay(schedules)
+	if options.DateRange != nil {
+		days = getScheduleDates(schedules)
+		mergedTimeBlocksByDay = mergeTimeBlocksByDate(schedules, days)
+	}
+
+	// Struct para recibir el resultado de cada coroutine
+	type daySweep struct {
+		segments []availabilitySegment
+		err      error
+	}
 
-	// Canal para recibir los resultados de las coroutines
-	results := make(chan []models.PlannerEvent, len(mergedTimeBlocksByDay))
+	// Canal para recibir los resultados de las coroutines. Tiene capacidad para todos los días, así que las
+	// coroutines terminan aunque la barrida se detenga antes de recopilarlos
+	results := make(chan daySweep, len(mergedTimeBlocksByDay))
 
-	// Lanzar una coroutine para encontrar los time slots disponibles por día
+	// Lanzar una coroutine para barrer los time blocks de cada día
 	for day, userTimeBlocks := range mergedTimeBlocksByDay {
 		go func(day string, userTimeBlocks []models.UserTimeBlock) {
-			availableSlots := findAvailableTimeSlotsByDay(day, userTimeBlocks, usersId)
-			results <- availableSlots
+			segments, err := sweepAvailabilityByDay(ctx, day, userTimeBlocks, ids)
+			results <- daySweep{segments: segments, err: err}
 		}(day, userTimeBlocks)
 	}
 
-	// Recopilar los resultados de todas las coroutines
-	availableSlotsByDay := make([]models.PlannerEvent, 0)
+	// Recopilar los segmentos de todas las coroutines, deteniéndose si la solicitud es cancelada
+	segmentsByDay := make(map[string][]availabilitySegment)
 	for range mergedTimeBlocksByDay {
-		availableSlots := <-results
-		availableSlotsByDay = append(availableSlotsByDay, availableSlots...)
+		select {
+		case result := <-results:
+			if result.err != nil {
+				return nil, nil, result.err
+			}
+			segmentsByDay[result.segments[0].day] = result.segments
+		case <-ctx.Done():
+			return nil, nil, checkContext(ctx)
+		}
+	}
+
+	// Los días cortos pueden terminar sin revisar el contexto, así que se revisa una última vez
+	if err := checkContext(ctx); err != nil {
+		return nil, nil, err
 	}
 
-	return availableSlotsByDay
+	return days, segmentsByDay, nil
 }
@@ -1,13 +1,18 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
 	"reflect"
 	"testing"
+	"time"
 
 	"Planner/models"
 )
 
-func TestFindAvailableTimeSlotsByDay(t *testing.T) {
+func TestSweepAvailabilityByDay(t *testing.T) {
 	// Caso de prueba: día sin ningún bloque de tiempo
 	day := "l"
 	userTimeBlocks := []models.UserTimeBlock{}
@@ -243,12 +248,223 @@ func TestFindAvailableTimeSlotsByDay(t *testing.T) {
 
 }
 
+func TestMergeBlocks(t *testing.T) {
+	// Caso de prueba: bloques solapados, contiguos y separados en desorden
+	timeBlocks := []models.TimeBlock{
+		{StartMinute: 600, EndMinute: 700},
+		{StartMinute: 0, EndMinute: 100},
+		{StartMinute: 50, EndMinute: 200},
+		{StartMinute: 201, EndMinute: 300},
+		{StartMinute: 120, EndMinute: 150},
+	}
+	expectedBlocks := []models.TimeBlock{
+		{StartMinute: 0, EndMinute: 300},
+		{StartMinute: 600, EndMinute: 700},
+	}
+	actualBlocks := mergeBlocks(timeBlocks)
+	if !reflect.DeepEqual(actualBlocks, expectedBlocks) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedBlocks, actualBlocks)
+	} else {
+		t.Logf("SUCCESS Expected: %v, Got: %v", expectedBlocks, actualBlocks)
+	}
+}
+
+func TestNormalizeScheduleWithOvernightBlocks(t *testing.T) {
+	// Caso de prueba: eventos que cruzan la medianoche, incluyendo uno del domingo al lunes
+	schedule := models.ScheduleModel{
+		ID:       "1",
+		Monday:   []models.TimeBlock{{StartMinute: 60, EndMinute: 120}},
+		Friday:   []models.TimeBlock{{StartMinute: 1320, EndMinute: 1440 + 60}},
+		Saturday: []models.TimeBlock{{StartMinute: 30, EndMinute: 90}},
+		Sunday:   []models.TimeBlock{{StartMinute: 1380, EndMinute: 1440 + 30}},
+	}
+	expectedSchedule := models.ScheduleModel{
+		ID:       "1",
+		Monday:   []models.TimeBlock{{StartMinute: 0, EndMinute: 30}, {StartMinute: 60, EndMinute: 120}},
+		Friday:   []models.TimeBlock{{StartMinute: 1320, EndMinute: 1439}},
+		Saturday: []models.TimeBlock{{StartMinute: 0, EndMinute: 90}},
+		Sunday:   []models.TimeBlock{{StartMinute: 1380, EndMinute: 1439}},
+	}
+	actualSchedule := normalizeSchedule(schedule)
+	if !reflect.DeepEqual(actualSchedule, expectedSchedule) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSchedule, actualSchedule)
+	} else {
+		t.Logf("SUCCESS Expected: %v, Got: %v", expectedSchedule, actualSchedule)
+	}
+}
+
 // Función auxiliar para aserciones de los resultados
 func assertTimeSlots(t *testing.T, day string, usersId []string, userTimeBlocks []models.UserTimeBlock, expectedSlots []models.PlannerEvent) {
-	actualSlots := findAvailableTimeSlotsByDay(day, userTimeBlocks, usersId)
+	segments, err := sweepAvailabilityByDay(context.Background(), day, userTimeBlocks, newUserTable(usersId))
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	actualSlots := make([]models.PlannerEvent, 0, len(segments))
+	for _, segment := range segments {
+		actualSlots = append(actualSlots, newPlannerEvent(segment))
+	}
 	if !reflect.DeepEqual(actualSlots, expectedSlots) {
 		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
 	} else {
 		t.Logf("SUCCESS Expected: %v, Got: %v", expectedSlots, actualSlots)
 	}
 }
+
+func TestNormalizeScheduleWithBuffers(t *testing.T) {
+	// Caso de prueba: el margen del lunes temprano pasa al domingo y el del domingo tarde pasa al lunes
+	schedule := models.ScheduleModel{
+		ID:           "1",
+		BufferBefore: 10,
+		BufferAfter:  15,
+		Monday:       []models.TimeBlock{{StartMinute: 0, EndMinute: 59}, {StartMinute: 600, EndMinute: 659}},
+		Wednesday:    []models.TimeBlock{{StartMinute: 480, EndMinute: 539}, {StartMinute: 560, EndMinute: 599}},
+		Sunday:       []models.TimeBlock{{StartMinute: 1400, EndMinute: 1439}},
+		Dates: map[string][]models.TimeBlock{
+			"2025-03-03": {{StartMinute: 5, EndMinute: 20}},
+			"2025-03-04": {{StartMinute: 1420, EndMinute: 1439}},
+			"2025-03-05": nil,
+		},
+	}
+	expectedSchedule := models.ScheduleModel{
+		ID:        "1",
+		Monday:    []models.TimeBlock{{StartMinute: 0, EndMinute: 74}, {StartMinute: 590, EndMinute: 674}},
+		Wednesday: []models.TimeBlock{{StartMinute: 470, EndMinute: 614}},
+		Sunday:    []models.TimeBlock{{StartMinute: 1390, EndMinute: 1439}},
+		Dates: map[string][]models.TimeBlock{
+			"2025-03-03": {{StartMinute: 0, EndMinute: 35}},
+			"2025-03-04": {{StartMinute: 1410, EndMinute: 1439}},
+			"2025-03-05": {{StartMinute: 0, EndMinute: 14}},
+		},
+	}
+	actualSchedule := normalizeSchedule(schedule)
+	if !reflect.DeepEqual(actualSchedule, expectedSchedule) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSchedule, actualSchedule)
+	} else {
+		t.Logf("SUCCESS Expected: %v, Got: %v", expectedSchedule, actualSchedule)
+	}
+}
+
+func TestMergeBlocksWithTentativeBlocks(t *testing.T) {
+	// Caso de prueba: los bloques tentativos se fusionan entre sí y los ocupados prevalecen donde se solapan
+	timeBlocks := []models.TimeBlock{
+		{StartMinute: 600, EndMinute: 700},
+		{StartMinute: 650, EndMinute: 800, Tentative: true},
+		{StartMinute: 0, EndMinute: 100, Tentative: true},
+		{StartMinute: 50, EndMinute: 60},
+		{StartMinute: 801, EndMinute: 900, Tentative: true},
+	}
+	expectedBlocks := []models.TimeBlock{
+		{StartMinute: 0, EndMinute: 49, Tentative: true},
+		{StartMinute: 50, EndMinute: 60},
+		{StartMinute: 61, EndMinute: 100, Tentative: true},
+		{StartMinute: 600, EndMinute: 700},
+		{StartMinute: 701, EndMinute: 900, Tentative: true},
+	}
+	actualBlocks := mergeBlocks(timeBlocks)
+	if !reflect.DeepEqual(actualBlocks, expectedBlocks) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedBlocks, actualBlocks)
+	} else {
+		t.Logf("SUCCESS Expected: %v, Got: %v", expectedBlocks, actualBlocks)
+	}
+}
+
+func TestSweepAvailabilityByDayWithTentativeBlocks(t *testing.T) {
+	// Caso de prueba: el almuerzo tentativo del usuario 1 se solapa con una clase del usuario 2
+	userTimeBlocks := []models.UserTimeBlock{
+		{UserID: "1", TimeBlock: models.TimeBlock{StartMinute: 720, EndMinute: 779, Tentative: true}},
+		{UserID: "2", TimeBlock: models.TimeBlock{StartMinute: 720, EndMinute: 749}},
+	}
+	expectedSlots := []models.PlannerEvent{
+		{DayOfWeek: "l", StartTime: "00:00", EndTime: "11:59", UsersAvailable: 2, Attendees: []string{"1", "2"}, Duration: 720},
+		{DayOfWeek: "l", StartTime: "12:00", EndTime: "12:29", UsersAvailable: 0, Attendees: []string{}, Duration: 30, TentativeAttendees: []string{"1"}},
+		{DayOfWeek: "l", StartTime: "12:30", EndTime: "12:59", UsersAvailable: 1, Attendees: []string{"2"}, Duration: 30, TentativeAttendees: []string{"1"}},
+		{DayOfWeek: "l", StartTime: "13:00", EndTime: "23:59", UsersAvailable: 2, Attendees: []string{"1", "2"}, Duration: 660},
+	}
+	assertTimeSlots(t, "l", []string{"1", "2"}, userTimeBlocks, expectedSlots)
+
+	// Caso de prueba: un bloque ocupado seguido de uno tentativo del mismo usuario
+	userTimeBlocks = []models.UserTimeBlock{
+		{UserID: "1", TimeBlock: models.TimeBlock{StartMinute: 600, EndMinute: 659}},
+		{UserID: "1", TimeBlock: models.TimeBlock{StartMinute: 660, EndMinute: 719, Tentative: true}},
+	}
+	expectedSlots = []models.PlannerEvent{
+		{DayOfWeek: "l", StartTime: "00:00", EndTime: "09:59", UsersAvailable: 1, Attendees: []string{"1"}, Duration: 600},
+		{DayOfWeek: "l", StartTime: "10:00", EndTime: "10:59", UsersAvailable: 0, Attendees: []string{}, Duration: 60},
+		{DayOfWeek: "l", StartTime: "11:00", EndTime: "11:59", UsersAvailable: 0, Attendees: []string{}, Duration: 60, TentativeAttendees: []string{"1"}},
+		{DayOfWeek: "l", StartTime: "12:00", EndTime: "23:59", UsersAvailable: 1, Attendees: []string{"1"}, Duration: 720},
+	}
+	assertTimeSlots(t, "l", []string{"1"}, userTimeBlocks, expectedSlots)
+}
+
+func TestGetAvailableTimeSlotsWithContext(t *testing.T) {
+	schedules := newBenchmarkSchedules(100, 10)
+
+	// Caso de prueba: una solicitud cancelada retorna el error del contexto
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slots, err := GetAvailableTimeSlots(ctx, schedules, models.PlannerOptions{Duration: 30})
+	var timeoutErr *TimeoutError
+	if !errors.Is(err, context.Canceled) || errors.As(err, &timeoutErr) || slots != nil {
+		t.Errorf("ERROR Expected: %v, Got: %v", context.Canceled, err)
+	}
+
+	// Caso de prueba: una solicitud que superó su tiempo límite retorna un TimeoutError
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	slots, err = GetAvailableTimeSlots(ctx, schedules, models.PlannerOptions{Duration: 30})
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) || slots != nil {
+		t.Errorf("ERROR Expected: %v, Got: %v", &TimeoutError{Err: context.DeadlineExceeded}, err)
+	}
+}
+
+// Función auxiliar para obtener los slots disponibles sin tiempo límite, fallando la prueba si hay un error
+func mustGetAvailableTimeSlots(tb testing.TB, schedules []models.ScheduleModel, options models.PlannerOptions) []models.PlannerEvent {
+	slots, err := GetAvailableTimeSlots(context.Background(), schedules, options)
+	if err != nil {
+		tb.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	return slots
+}
+
+func BenchmarkSweepSchedules(b *testing.B) {
+	for _, users := range []int{100, 1000, 5000} {
+		b.Run(fmt.Sprintf("users=%d", users), func(b *testing.B) {
+			schedules := newBenchmarkSchedules(users, 10)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				sweepSchedules(context.Background(), schedules, models.PlannerOptions{})
+			}
+		})
+	}
+}
+
+func BenchmarkGetAvailableTimeSlots(b *testing.B) {
+	for _, users := range []int{100, 1000, 5000} {
+		b.Run(fmt.Sprintf("users=%d", users), func(b *testing.B) {
+			schedules := newBenchmarkSchedules(users, 10)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				mustGetAvailableTimeSlots(b, schedules, models.PlannerOptions{Duration: 30})
+			}
+		})
+	}
+}
+
+// Función auxiliar para generar los horarios de users usuarios con eventsPerUser eventos aleatorios de 30 a 120
+// minutos en horario laboral. Los eventos empiezan y terminan en cualquier minuto, como en un calendario real, para
+// que la barrida no se beneficie de límites alineados. La semilla es fija para que las mediciones sean comparables
+func newBenchmarkSchedules(users int, eventsPerUser int) []models.ScheduleModel {
+	random := rand.New(rand.NewSource(1))
+	schedules := make([]models.ScheduleModel, users)
+	for i := range schedules {
+		schedule := models.ScheduleModel{ID: fmt.Sprintf("user-%d", i)}
+		for j := 0; j < eventsPerUser; j++ {
+			start := 8*60 + random.Intn(10*60)
+			block := models.TimeBlock{StartMinute: start, EndMinute: start + 30 + random.Intn(91) - 1}
+			timeBlocks, _ := getDayTimeBlocks(&schedule, weekDays[random.Intn(5)])
+			*timeBlocks = append(*timeBlocks, block)
+		}
+		schedules[i] = schedule
+	}
+	return schedules
+}
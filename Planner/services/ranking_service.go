@@ -0,0 +1,205 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"Planner/models"
+)
+
+// Pesos por defecto de cada criterio del puntaje
+var defaultScoreWeights = map[string]float64{
+	"attendance": 0.4,
+	"duration":   0.2,
+	"timeOfDay":  0.2,
+	"proximity":  0.2,
+}
+
+// Horas a evitar por defecto: la madrugada y la hora de almuerzo
+var defaultAvoidTimes = []models.TimeRange{
+	{StartTime: "0000", EndTime: "0759"},
+	{StartTime: "1200", EndTime: "1359"},
+}
+
+// Número de slots y distancia a un evento cercano por defecto
+const defaultRankTop = 10
+const defaultProximityMinutes = 60
+
+// Función para calcular el puntaje de cada slot con al menos minAttendees asistentes, ordenarlos de mayor a menor
+// puntaje y conservar los mejores. Los slots con el mismo puntaje mantienen su orden original. Los horarios deben
+// estar sin normalizar para que la cercanía solo considere los eventos de los usuarios, sin sus márgenes
+func rankSlots(slots []models.PlannerEvent, schedules []models.ScheduleModel, minAttendees int, duration int, rank models.RankOptions) []models.PlannerEvent {
+	// Combinar los pesos de la solicitud con los pesos por defecto
+	weights := make(map[string]float64, len(defaultScoreWeights))
+	for criterion, weight := range defaultScoreWeights {
+		weights[criterion] = weight
+	}
+	for criterion, weight := range rank.Weights {
+		weights[criterion] = weight
+	}
+
+	avoidBlocks := getAvoidBlocks(rank.AvoidTimes)
+	proximityMinutes := rank.ProximityMinutes
+	if proximityMinutes == 0 {
+		proximityMinutes = defaultProximityMinutes
+	}
+
+	schedulesByID := make(map[string]*models.ScheduleModel, len(schedules))
+	for i := range schedules {
+		schedulesByID[schedules[i].ID] = &schedules[i]
+	}
+
+	// Descartar los slots sin suficientes asistentes y encontrar la duración del slot más largo
+	var candidates []models.PlannerEvent
+	maxDuration := 0
+	for _, slot := range slots {
+		if slot.UsersAvailable >= max(minAttendees, 1) {
+			candidates = append(candidates, slot)
+			maxDuration = max(maxDuration, slot.Duration)
+		}
+	}
+
+	for i, slot := range candidates {
+		// Los minutos del slot son relativos al día (o fecha) en que inicia
+		day := slot.DayOfWeek
+		if slot.Date != "" {
+			day = slot.Date
+		}
+		start, _ := convertToMinutes(strings.Replace(slot.StartTime, ":", "", 1))
+		end := start + slot.Duration
+
+		// La reunión dura lo solicitado o, sin una duración, ocupa todo el slot
+		meetingDuration := duration
+		if meetingDuration <= 0 {
+			meetingDuration = slot.Duration
+		}
+
+		nearbyAttendees := 0
+		for _, userID := range slot.Attendees {
+			if schedule, ok := schedulesByID[userID]; ok && hasNearbyEvent(schedule, day, start, end, proximityMinutes) {
+				nearbyAttendees++
+			}
+		}
+
+		score := &models.SlotScore{
+			Attendance: float64(slot.UsersAvailable) / float64(len(schedules)),
+			Duration:   float64(slot.Duration) / float64(maxDuration),
+			TimeOfDay:  math.Min(1, float64(longestUnavoidedRun(start, end, avoidBlocks))/float64(meetingDuration)),
+			Proximity:  float64(nearbyAttendees) / float64(slot.UsersAvailable),
+		}
+
+		// El puntaje total es el promedio de los criterios ponderado por sus pesos
+		totalWeight := weights["attendance"] + weights["duration"] + weights["timeOfDay"] + weights["proximity"]
+		if totalWeight > 0 {
+			score.Total = (weights["attendance"]*score.Attendance + weights["duration"]*score.Duration +
+				weights["timeOfDay"]*score.TimeOfDay + weights["proximity"]*score.Proximity) / totalWeight
+		}
+
+		score.Total = roundScore(score.Total)
+		score.Attendance = roundScore(score.Attendance)
+		score.Duration = roundScore(score.Duration)
+		score.TimeOfDay = roundScore(score.TimeOfDay)
+		score.Proximity = roundScore(score.Proximity)
+		candidates[i].Score = score
+	}
+
+	// Ordenar los slots de mayor a menor puntaje y conservar los mejores
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score.Total > candidates[j].Score.Total
+	})
+
+	top := rank.Top
+	if top == 0 {
+		top = defaultRankTop
+	}
+	if len(candidates) > top {
+		candidates = candidates[:top]
+	}
+
+	rankedSlots := make([]models.PlannerEvent, 0, len(candidates))
+	return append(rankedSlots, candidates...)
+}
+
+// Función para convertir las horas a evitar en TimeBlocks, usando las horas por defecto si la solicitud no las envía
+func getAvoidBlocks(avoidTimes []models.TimeRange) []models.TimeBlock {
+	if avoidTimes == nil {
+		avoidTimes = defaultAvoidTimes
+	}
+
+	avoidBlocks := make([]models.TimeBlock, 0, len(avoidTimes))
+	for _, avoidTime := range avoidTimes {
+		// Las horas ya fueron validadas por el middleware
+		block, err := convertToTimeBlock(models.Event{StartTime: avoidTime.StartTime, EndTime: avoidTime.EndTime})
+		if err == nil {
+			avoidBlocks = append(avoidBlocks, block)
+		}
+	}
+	return avoidBlocks
+}
+
+// Función para calcular los minutos consecutivos más largos de [start, end) que no caen en las horas a evitar,
+// las cuales se repiten cada día (incluyendo las del día anterior que cruzan la medianoche)
+func longestUnavoidedRun(start int, end int, avoidBlocks []models.TimeBlock) int {
+	var avoided []models.TimeBlock
+	for day := floorDiv(start, 1440) - 1; day <= floorDiv(end-1, 1440); day++ {
+		for _, block := range avoidBlocks {
+			avoided = append(avoided, models.TimeBlock{StartMinute: day*1440 + block.StartMinute, EndMinute: day*1440 + block.EndMinute + 1})
+		}
+	}
+	sort.Slice(avoided, func(i, j int) bool {
+		return avoided[i].StartMinute < avoided[j].StartMinute
+	})
+
+	// Recorrer los intervalos a evitar midiendo los huecos entre ellos dentro del slot
+	longest, cursor := 0, start
+	for _, block := range avoided {
+		if block.EndMinute <= cursor {
+			continue
+		}
+		if block.StartMinute >= end {
+			break
+		}
+		longest = max(longest, block.StartMinute-cursor)
+		cursor = block.EndMinute
+	}
+	if cursor < end {
+		longest = max(longest, end-cursor)
+	}
+	return longest
+}
+
+// Función para verificar si un usuario tiene un evento a menos de proximityMinutes minutos antes o después del slot
+// [start, end), revisando también el día anterior y el siguiente. El tiempo fuera del horario laboral o de la
+// disponibilidad del usuario no es un evento, aunque siempre limita a los slots
+func hasNearbyEvent(schedule *models.ScheduleModel, day string, start int, end int, proximityMinutes int) bool {
+	for offset := floorDiv(start, 1440) - 1; offset <= floorDiv(end-1, 1440)+1; offset++ {
+		for _, block := range getBusyBlocks(schedule, shiftDay(day, offset)) {
+			if block.Outside {
+				continue
+			}
+			blockStart, blockEnd := offset*1440+block.StartMinute, offset*1440+block.EndMinute+1
+			if (blockEnd <= start && start-blockEnd <= proximityMinutes) || (blockStart >= end && blockStart-end <= proximityMinutes) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Función auxiliar para obtener los TimeBlocks de un día de la semana o de una fecha del ScheduleModel
+func getBusyBlocks(schedule *models.ScheduleModel, day string) []models.TimeBlock {
+	if isDate(day) {
+		return schedule.Dates[day]
+	}
+	timeBlocks, err := getDayTimeBlocks(schedule, day)
+	if err != nil {
+		return nil
+	}
+	return *timeBlocks
+}
+
+// Función auxiliar para redondear un puntaje a tres decimales
+func roundScore(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
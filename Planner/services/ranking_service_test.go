@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestLongestUnavoidedRun(t *testing.T) {
+	avoidBlocks := getAvoidBlocks(nil)
+
+	// Caso de prueba: un slot de 07:00 a 15:00 tiene libre de 08:00 a 12:00 entre la madrugada y el almuerzo
+	if run := longestUnavoidedRun(420, 900, avoidBlocks); run != 240 {
+		t.Errorf("ERROR Expected: 240, Got: %d", run)
+	}
+
+	// Caso de prueba: un slot de 23:00 a 09:00 del día siguiente evita la madrugada del día siguiente
+	if run := longestUnavoidedRun(1380, 1440+540, avoidBlocks); run != 60 {
+		t.Errorf("ERROR Expected: 60, Got: %d", run)
+	}
+
+	// Caso de prueba: sin horas a evitar, todo el slot es válido
+	if run := longestUnavoidedRun(360, 480, getAvoidBlocks([]models.TimeRange{})); run != 120 {
+		t.Errorf("ERROR Expected: 120, Got: %d", run)
+	}
+}
+
+func TestGetAvailableTimeSlotsWithRank(t *testing.T) {
+	// Caso de prueba: dos usuarios libres el lunes de 06:00 a 08:00 y el martes de 14:00 a 16:00
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	schedule := models.ScheduleModel{
+		Monday:    []models.TimeBlock{{StartMinute: 0, EndMinute: 359}, {StartMinute: 480, EndMinute: 1439}},
+		Tuesday:   []models.TimeBlock{{StartMinute: 0, EndMinute: 839}, {StartMinute: 960, EndMinute: 1439}},
+		Wednesday: busyDay,
+		Thursday:  busyDay,
+		Friday:    busyDay,
+		Saturday:  busyDay,
+		Sunday:    busyDay,
+	}
+	newSchedules := func() []models.ScheduleModel {
+		first, second := schedule, schedule
+		first.ID, second.ID = "1", "2"
+		return []models.ScheduleModel{first, second}
+	}
+
+	mondaySlot := models.PlannerEvent{
+		DayOfWeek:       "l",
+		StartTime:       "06:00",
+		EndTime:         "07:59",
+		UsersAvailable:  2,
+		Attendees:       []string{"1", "2"},
+		Duration:        120,
+		LatestStartTime: "07:00",
+		Score:           &models.SlotScore{Total: 0.8, Attendance: 1, Duration: 1, TimeOfDay: 0, Proximity: 1},
+	}
+	tuesdaySlot := models.PlannerEvent{
+		DayOfWeek:       "m",
+		StartTime:       "14:00",
+		EndTime:         "15:59",
+		UsersAvailable:  2,
+		Attendees:       []string{"1", "2"},
+		Duration:        120,
+		LatestStartTime: "15:00",
+		Score:           &models.SlotScore{Total: 1, Attendance: 1, Duration: 1, TimeOfDay: 1, Proximity: 1},
+	}
+
+	// El slot de la madrugada queda después del slot de la tarde
+	options := models.PlannerOptions{Duration: 60, Rank: &models.RankOptions{}}
+	expectedSlots := []models.PlannerEvent{tuesdaySlot, mondaySlot}
+	actualSlots := mustGetAvailableTimeSlots(t, newSchedules(), options)
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+
+	// Con solo el mejor slot
+	options.Rank.Top = 1
+	expectedSlots = []models.PlannerEvent{tuesdaySlot}
+	actualSlots = mustGetAvailableTimeSlots(t, newSchedules(), options)
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+
+	// Sin peso para la hora del día, los dos slots empatan y mantienen el orden de los días
+	options.Rank = &models.RankOptions{Weights: map[string]float64{"timeOfDay": 0}}
+	mondaySlot.Score = &models.SlotScore{Total: 1, Attendance: 1, Duration: 1, TimeOfDay: 0, Proximity: 1}
+	expectedSlots = []models.PlannerEvent{mondaySlot, tuesdaySlot}
+	actualSlots = mustGetAvailableTimeSlots(t, newSchedules(), options)
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+}
+
+func TestGetAvailableTimeSlotsWithRankProximity(t *testing.T) {
+	// Caso de prueba: dos usuarios sin eventos que solo trabajan el lunes de 14:00 a 16:00
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{"1": {}, "2": {}},
+		WorkingHours: models.WorkingHours{
+			Default: []models.Event{{DayOfWeek: "l", StartTime: "1400", EndTime: "1559"}},
+		},
+		Options: models.PlannerOptions{Duration: 60, Rank: &models.RankOptions{}},
+	}
+	slot := models.PlannerEvent{
+		DayOfWeek:       "l",
+		StartTime:       "14:00",
+		EndTime:         "15:59",
+		UsersAvailable:  2,
+		Attendees:       []string{"1", "2"},
+		Duration:        120,
+		LatestStartTime: "15:00",
+		Score:           &models.SlotScore{Total: 0.8, Attendance: 1, Duration: 1, TimeOfDay: 1, Proximity: 0},
+	}
+
+	// Los límites del horario laboral no cuentan como eventos cercanos
+	assertRankedSlots(t, request, []models.PlannerEvent{slot})
+
+	// Un evento del usuario 1 que termina justo antes del horario laboral sí cuenta, aunque tenga margen
+	request.Users["1"] = []models.Event{{DayOfWeek: "l", StartTime: "1300", EndTime: "1359"}}
+	request.Options.Buffer = models.Buffer{After: 15}
+	slot.StartTime = "14:15"
+	slot.Duration = 105
+	slot.Score = &models.SlotScore{Total: 0.9, Attendance: 1, Duration: 1, TimeOfDay: 1, Proximity: 0.5}
+	assertRankedSlots(t, request, []models.PlannerEvent{slot})
+}
+
+// Función auxiliar para aserciones de los slots recomendados a partir de una solicitud
+func assertRankedSlots(t *testing.T, request models.PlannerRequest, expectedSlots []models.PlannerEvent) {
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	actualSlots := mustGetAvailableTimeSlots(t, schedules, request.Options)
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+}
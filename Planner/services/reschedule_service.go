@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"Planner/models"
+)
+
+// Número máximo de eventos a mover por defecto
+const defaultMaxMoves = 2
+
+// Struct para representar un evento movible con su posición en la línea de tiempo de los días a planificar
+type movableEvent struct {
+	userID string
+	index  int // Posición del evento en la lista de eventos del usuario
+	event  models.Event
+	start  int // Minuto de inicio desde la medianoche del primer día
+	length int // Duración del evento en minutos
+}
+
+// Struct para representar una solución: los movimientos, la reunión que liberan y los minutos que se desplazan los
+// eventos en total
+type rescheduleCandidate struct {
+	moves        []models.EventMove
+	slot         models.PlannerEvent
+	start        int // Minuto de inicio de la reunión desde la medianoche del primer día
+	displacement int
+}
+
+// FindReschedule busca el menor número de eventos movibles que, al moverse a otro momento libre de su usuario,
+// liberan una reunión de options.Duration minutos en la que todos los usuarios están libres. Entre las soluciones
+// con el mismo número de movimientos se prefiere la que desplaza menos minutos los eventos y luego la reunión más
+// temprana. Si ningún conjunto de hasta maxMoves eventos libera una reunión, el resultado no tiene slot. Los horarios
+// deben ser los de BuildSchedules para la misma solicitud, que se reconstruyen sin los eventos de cada combinación
+func FindReschedule(ctx context.Context, request models.PlannerRequest, schedules []models.ScheduleModel) (models.Reschedule, error) {
+	// Días de la línea de tiempo: la semana genérica o las fechas del rango
+	days := weekDays
+	if request.Options.DateRange != nil {
+		days = getDatesInRange(*request.Options.DateRange)
+	}
+
+	// Obtener los eventos movibles en el orden de los horarios, que están ordenados por ID de usuario
+	var movables []movableEvent
+	for _, schedule := range schedules {
+		for i, event := range request.Users[schedule.ID] {
+			if !event.Movable {
+				continue
+			}
+
+			// Los eventos puntuales fuera del rango de fechas no afectan a la reunión
+			day := event.DayOfWeek
+			if event.Date != "" {
+				day = event.Date
+			}
+			position := slices.Index(days, day)
+			if position < 0 {
+				continue
+			}
+
+			block, err := convertToTimeBlock(event)
+			if err != nil {
+				return models.Reschedule{}, err
+			}
+			movables = append(movables, movableEvent{
+				userID: schedule.ID,
+				index:  i,
+				event:  event,
+				start:  position*1440 + block.StartMinute,
+				length: block.EndMinute - block.StartMinute + 1,
+			})
+		}
+	}
+
+	maxMoves := defaultMaxMoves
+	if request.Options.Reschedule != nil && request.Options.Reschedule.MaxMoves > 0 {
+		maxMoves = request.Options.Reschedule.MaxMoves
+	}
+
+	// Probar primero sin mover ningún evento y luego con cada vez más movimientos, evaluando todas las
+	// combinaciones de un mismo tamaño para quedarse con la que menos desplaza los eventos
+	for count := 0; count <= min(maxMoves, len(movables)); count++ {
+		var best *rescheduleCandidate
+		combination := make([]int, count)
+		for i := range combination {
+			combination[i] = i
+		}
+		for {
+			candidate, err := tryMoves(ctx, request, schedules, days, movables, combination)
+			if err != nil {
+				return models.Reschedule{}, err
+			}
+			if candidate != nil && (best == nil || isBetterCandidate(*candidate, *best)) {
+				best = candidate
+			}
+			if !nextCombination(combination, len(movables)) {
+				break
+			}
+		}
+		if best != nil {
+			return models.Reschedule{Moves: best.moves, Slot: &best.slot}, nil
+		}
+	}
+
+	return models.Reschedule{Moves: []models.EventMove{}}, nil
+}
+
+// Función auxiliar para avanzar a la siguiente combinación de índices crecientes menores que n, en orden
+// lexicográfico. Retorna false si no quedan combinaciones
+func nextCombination(combination []int, n int) bool {
+	for i := len(combination) - 1; i >= 0; i-- {
+		if combination[i] < n-len(combination)+i {
+			combination[i]++
+			for j := i + 1; j < len(combination); j++ {
+				combination[j] = combination[j-1] + 1
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Función auxiliar para comparar dos soluciones: primero la que desplaza menos minutos los eventos y luego la
+// reunión más temprana
+func isBetterCandidate(a, b rescheduleCandidate) bool {
+	if a.displacement != b.displacement {
+		return a.displacement < b.displacement
+	}
+	return a.start < b.start
+}
+
+// Función para quitar de los horarios los eventos de una combinación y buscar, entre las ventanas en las que todos
+// los usuarios quedan libres, la mejor reunión que se solapa con todos esos eventos y el nuevo momento de cada uno.
+// Retorna nil si ninguna ventana lo permite
+func tryMoves(ctx context.Context, request models.PlannerRequest, baseSchedules []models.ScheduleModel, days []string, movables []movableEvent, combination []int) (*rescheduleCandidate, error) {
+	moved := make([]movableEvent, len(combination))
+	removed := make(map[string]map[int]bool)
+	for i, c := range combination {
+		moved[i] = movables[c]
+		if removed[moved[i].userID] == nil {
+			removed[moved[i].userID] = make(map[int]bool)
+		}
+		removed[moved[i].userID][moved[i].index] = true
+	}
+
+	// Reconstruir solo los horarios de los usuarios con eventos quitados, sobre una copia de los horarios
+	schedules := append([]models.ScheduleModel{}, baseSchedules...)
+	for i, schedule := range schedules {
+		if removed[schedule.ID] == nil {
+			continue
+		}
+		var events []models.Event
+		for j, event := range request.Users[schedule.ID] {
+			if !removed[schedule.ID][j] {
+				events = append(events, event)
+			}
+		}
+		userRequest := request
+		userRequest.Users = map[string][]models.Event{schedule.ID: events}
+		rebuiltSchedules, err := BuildSchedules(ctx, userRequest)
+		if err != nil {
+			return nil, err
+		}
+		schedules[i] = rebuiltSchedules[0]
+	}
+
+	// Barrer los horarios, que quedan normalizados, y buscar las ventanas con todos los usuarios
+	sweepDays, segmentsByDay, err := sweepSchedules(ctx, schedules, request.Options)
+	if err != nil {
+		return nil, err
+	}
+	timelines, circular := getTimelines(sweepDays, segmentsByDay, request.Options)
+
+	var best *rescheduleCandidate
+	for _, segments := range timelines {
+		windows, err := findMeetingWindows(ctx, segments, len(schedules), request.Options.Duration, request.Options.Granularity, circular)
+		if err != nil {
+			return nil, err
+		}
+		for _, window := range windows {
+			candidate, ok := placeMeeting(window, schedules, baseSchedules, days, moved, request.Options, circular)
+			if ok && (best == nil || isBetterCandidate(candidate, *best)) {
+				best = &candidate
+			}
+		}
+	}
+	return best, nil
+}
+
+// Función para ubicar la reunión lo más temprano posible dentro de una ventana, solapándose con todos los eventos
+// movidos, y mover cada evento al momento libre de su usuario más cercano a su inicio original. Los horarios deben
+// estar normalizados y baseSchedules son los mismos horarios antes de normalizar, que conservan los márgenes
+func placeMeeting(window availabilitySegment, schedules []models.ScheduleModel, baseSchedules []models.ScheduleModel, days []string, moved []movableEvent, options models.PlannerOptions, circular bool) (rescheduleCandidate, bool) {
+	period := len(days) * 1440
+	base := slices.Index(days, window.day) * 1440
+	windowStart, windowEnd := base+window.start, base+window.end
+
+	// La reunión puede iniciar entre lo y hi, y cada evento movido debe solaparse con ella porque si no, moverlo
+	// no sería necesario
+	lo, hi := windowStart, windowEnd-options.Duration
+	for _, event := range moved {
+		start, ok := alignToWindow(event, windowStart, windowEnd, period, circular)
+		if !ok {
+			return rescheduleCandidate{}, false
+		}
+		lo = max(lo, start-options.Duration+1)
+		hi = min(hi, start+event.length-1)
+	}
+	if options.Granularity > 0 {
+		lo = -floorDiv(-lo, options.Granularity) * options.Granularity
+	}
+	if lo > hi {
+		return rescheduleCandidate{}, false
+	}
+
+	candidate := rescheduleCandidate{moves: []models.EventMove{}, start: lo}
+	destinations := make(map[string][]models.TimeBlock)
+	for _, event := range moved {
+		i := slices.IndexFunc(schedules, func(schedule models.ScheduleModel) bool {
+			return schedule.ID == event.userID
+		})
+		before, after := baseSchedules[i].BufferBefore, baseSchedules[i].BufferAfter
+
+		// El evento no puede solaparse con los bloques ocupados del usuario, que ya incluyen sus márgenes, ni con la
+		// reunión o los eventos ya movidos ampliados por el margen del evento
+		busyBlocks := getTimelineBlocks(&schedules[i], days)
+		busyBlocks = append(busyBlocks, models.TimeBlock{StartMinute: lo - after, EndMinute: lo + options.Duration - 1 + before})
+		if circular {
+			busyBlocks = append(busyBlocks, models.TimeBlock{StartMinute: lo - period - after, EndMinute: lo - period + options.Duration - 1 + before})
+		}
+		busyBlocks = append(busyBlocks, destinations[event.userID]...)
+
+		start, ok := findEventDestination(busyBlocks, event, period)
+		if !ok {
+			return rescheduleCandidate{}, false
+		}
+		destinations[event.userID] = append(destinations[event.userID], models.TimeBlock{StartMinute: start - before, EndMinute: start + event.length - 1 + after})
+		candidate.displacement += max(start-event.start, event.start-start)
+
+		// Expresar el nuevo momento con el formato de los eventos de la solicitud
+		to := event.event
+		to.DayOfWeek, to.Date = describeDay(days[start/1440])
+		end := (start + event.length - 1) % 1440
+		to.StartTime = fmt.Sprintf("%02d%02d", (start%1440)/60, (start%1440)%60)
+		to.EndTime = fmt.Sprintf("%02d%02d", end/60, end%60)
+		candidate.moves = append(candidate.moves, models.EventMove{UserID: event.userID, From: event.event, To: to})
+	}
+
+	// La reunión tiene a todos los usuarios de la ventana
+	candidate.slot = newPlannerEvent(availabilitySegment{
+		day:       window.day,
+		start:     lo - base,
+		end:       lo - base + options.Duration,
+		attendees: window.attendees,
+		tentative: newUserSet(window.attendees.ids),
+	})
+	return candidate, true
+}
+
+// Función auxiliar para obtener el inicio de un evento movido en la vuelta de la línea de tiempo en la que se solapa
+// con la ventana [start, end). Retorna false si el evento no toca la ventana
+func alignToWindow(event movableEvent, start int, end int, period int, circular bool) (int, bool) {
+	shifts := []int{0}
+	if circular {
+		shifts = []int{0, period, -period}
+	}
+	for _, shift := range shifts {
+		if eventStart := event.start + shift; eventStart < end && eventStart+event.length > start {
+			return eventStart, true
+		}
+	}
+	return 0, false
+}
+
+// Función auxiliar para obtener los bloques ocupados de un horario normalizado en minutos desde la medianoche del primer día
+func getTimelineBlocks(schedule *models.ScheduleModel, days []string) []models.TimeBlock {
+	var timelineBlocks []models.TimeBlock
+	for i, day := range days {
+		for _, block := range getBusyBlocks(schedule, day) {
+			timelineBlocks = append(timelineBlocks, models.TimeBlock{StartMinute: block.StartMinute + i*1440, EndMinute: block.EndMinute + i*1440})
+		}
+	}
+	return timelineBlocks
+}
+
+// Función para encontrar el inicio libre más cercano al inicio original de un evento movido, sin solaparse con
+// ningún bloque ocupado y sin salir de la línea de tiempo de period minutos. Los bloques tentativos también cuentan
+// como ocupados. Si dos inicios están igual de cerca, se prefiere el más temprano
+func findEventDestination(busyBlocks []models.TimeBlock, event movableEvent, period int) (int, bool) {
+	busyBlocks = append(mergeBlocksOfSameLevel(busyBlocks), models.TimeBlock{StartMinute: period, EndMinute: period})
+
+	best, found := 0, false
+	free := 0
+	for _, block := range busyBlocks {
+		// El hueco libre va de free hasta el minuto anterior al bloque
+		if latest := min(block.StartMinute, period) - event.length; latest >= free {
+			start := min(max(event.start, free), latest)
+			if distance := max(start-event.start, event.start-start); !found || distance < max(best-event.start, event.start-best) {
+				best, found = start, true
+			}
+		}
+		free = max(free, block.EndMinute+1)
+	}
+	return best, found
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestFindReschedule(t *testing.T) {
+	// Caso de prueba: los usuarios solo se reúnen el lunes de 08:00 a 12:00 y ningún hueco común dura una hora
+	study := models.Event{DayOfWeek: "l", StartTime: "0800", EndTime: "0929", Movable: true}
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {study},
+			"2": {{DayOfWeek: "l", StartTime: "1000", EndTime: "1129"}},
+		},
+		WorkingHours: models.WorkingHours{
+			Default: []models.Event{{DayOfWeek: "l", StartTime: "0800", EndTime: "1159"}},
+		},
+		Options: models.PlannerOptions{Duration: 60},
+	}
+
+	// Mover la sesión de estudio libera la primera hora, y la sesión pasa al hueco libre más cercano
+	movedStudy := study
+	movedStudy.StartTime, movedStudy.EndTime = "0900", "1029"
+	expectedReschedule := models.Reschedule{
+		Moves: []models.EventMove{{UserID: "1", From: study, To: movedStudy}},
+		Slot: &models.PlannerEvent{
+			DayOfWeek: "l", StartTime: "08:00", EndTime: "08:59", UsersAvailable: 2, Attendees: []string{"1", "2"}, Duration: 60,
+		},
+	}
+	assertReschedule(t, request, expectedReschedule)
+
+	// Si la sesión no se puede mover, no hay solución
+	request.Users["1"] = []models.Event{{DayOfWeek: "l", StartTime: "0800", EndTime: "0929"}}
+	assertReschedule(t, request, models.Reschedule{Moves: []models.EventMove{}})
+
+	// Si ya hay un hueco común, no se mueve ningún evento
+	request.Options.Duration = 30
+	expectedReschedule = models.Reschedule{
+		Moves: []models.EventMove{},
+		Slot: &models.PlannerEvent{
+			DayOfWeek: "l", StartTime: "09:30", EndTime: "09:59", UsersAvailable: 2, Attendees: []string{"1", "2"}, Duration: 30,
+		},
+	}
+	assertReschedule(t, request, expectedReschedule)
+}
+
+func TestFindRescheduleWithTwoMoves(t *testing.T) {
+	// Caso de prueba: una reunión de dos horas que solo cabe si se mueven los eventos de dos usuarios
+	gym := models.Event{DayOfWeek: "l", StartTime: "0800", EndTime: "0859", Movable: true}
+	study := models.Event{DayOfWeek: "l", StartTime: "0900", EndTime: "0959", Movable: true}
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {gym},
+			"2": {study},
+			"3": {{DayOfWeek: "l", StartTime: "1000", EndTime: "1159"}},
+		},
+		WorkingHours: models.WorkingHours{
+			Default: []models.Event{{DayOfWeek: "l", StartTime: "0800", EndTime: "1159"}},
+		},
+		Options: models.PlannerOptions{Duration: 120, Reschedule: &models.RescheduleOptions{MaxMoves: 1}},
+	}
+
+	// Con un solo movimiento no hay solución
+	assertReschedule(t, request, models.Reschedule{Moves: []models.EventMove{}})
+
+	// Con dos movimientos, ambos eventos pasan después de la reunión
+	request.Options.Reschedule = nil
+	movedGym, movedStudy := gym, study
+	movedGym.StartTime, movedGym.EndTime = "1000", "1059"
+	movedStudy.StartTime, movedStudy.EndTime = "1000", "1059"
+	expectedReschedule := models.Reschedule{
+		Moves: []models.EventMove{{UserID: "1", From: gym, To: movedGym}, {UserID: "2", From: study, To: movedStudy}},
+		Slot: &models.PlannerEvent{
+			DayOfWeek: "l", StartTime: "08:00", EndTime: "09:59", UsersAvailable: 3, Attendees: []string{"1", "2", "3"}, Duration: 120,
+		},
+	}
+	assertReschedule(t, request, expectedReschedule)
+}
+
+func TestNextCombination(t *testing.T) {
+	// Caso de prueba: las combinaciones de dos elementos de cuatro, en orden lexicográfico
+	combination := []int{0, 1}
+	actualCombinations := [][]int{{0, 1}}
+	for nextCombination(combination, 4) {
+		actualCombinations = append(actualCombinations, append([]int{}, combination...))
+	}
+	expectedCombinations := [][]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}
+	if !reflect.DeepEqual(actualCombinations, expectedCombinations) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedCombinations, actualCombinations)
+	}
+}
+
+// Función auxiliar para aserciones de los movimientos sugeridos y el slot que liberan
+func assertReschedule(t *testing.T, request models.PlannerRequest, expectedReschedule models.Reschedule) {
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	actualReschedule, err := FindReschedule(context.Background(), request, schedules)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(actualReschedule, expectedReschedule) {
+		t.Errorf("ERROR Expected: %+v, Got: %+v", expectedReschedule, actualReschedule)
+	}
+}
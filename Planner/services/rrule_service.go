@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"Planner/models"
+)
+
+// Struct para representar una regla de recurrencia semanal de iCalendar (RRULE)
+type recurrenceRule struct {
+	interval int      // Cada cuántas semanas se repite el evento
+	byDay    []string // Días de la semana ("l" a "d") en los que ocurre el evento
+	until    string   // Última fecha "AAAA-MM-DD" en la que puede ocurrir el evento, vacío si no tiene
+	count    int      // Número máximo de ocurrencias, 0 si no tiene
+}
+
+// Días de la semana de iCalendar y su letra correspondiente
+var iCalendarDays = map[string]string{"MO": "l", "TU": "m", "WE": "i", "TH": "j", "FR": "v", "SA": "s", "SU": "d"}
+
+// Función para interpretar una RRULE semanal, por ejemplo "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20240531"
+func parseRecurrenceRule(rule string) (recurrenceRule, error) {
+	recurrence := recurrenceRule{interval: 1}
+	frequency := ""
+
+	for _, part := range strings.Split(strings.TrimPrefix(rule, "RRULE:"), ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return recurrence, fmt.Errorf("Invalid RRULE part '%s' in '%s'", part, rule)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			frequency = strings.ToUpper(value)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return recurrence, fmt.Errorf("Invalid RRULE interval '%s' in '%s'", value, rule)
+			}
+			recurrence.interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return recurrence, fmt.Errorf("Invalid RRULE count '%s' in '%s'", value, rule)
+			}
+			recurrence.count = count
+		case "UNTIL":
+			// Se acepta tanto una fecha como una fecha y hora, de la que solo se usa la fecha
+			until, err := time.Parse("20060102", value[:min(len(value), 8)])
+			if err != nil {
+				return recurrence, fmt.Errorf("Invalid RRULE until '%s' in '%s'", value, rule)
+			}
+			recurrence.until = until.Format(DateLayout)
+		case "BYDAY":
+			for _, iCalendarDay := range strings.Split(strings.ToUpper(value), ",") {
+				day, ok := iCalendarDays[iCalendarDay]
+				if !ok {
+					return recurrence, fmt.Errorf("Invalid RRULE day '%s' in '%s'", iCalendarDay, rule)
+				}
+				recurrence.byDay = append(recurrence.byDay, day)
+			}
+		case "WKST":
+			// Las semanas siempre inician el lunes
+			if strings.ToUpper(value) != "MO" {
+				return recurrence, fmt.Errorf("Unsupported RRULE week start '%s' in '%s'", value, rule)
+			}
+		default:
+			return recurrence, fmt.Errorf("Unsupported RRULE part '%s' in '%s'", name, rule)
+		}
+	}
+
+	if frequency != "WEEKLY" {
+		return recurrence, fmt.Errorf("Unsupported RRULE frequency '%s' in '%s', only WEEKLY is supported", frequency, rule)
+	}
+	if recurrence.count > 0 && recurrence.until != "" {
+		return recurrence, fmt.Errorf("RRULE '%s' can't have both COUNT and UNTIL", rule)
+	}
+
+	return recurrence, nil
+}
+
+// Función para expandir los eventos con RRULE. Sin un rango de fechas, cada día de la regla se convierte en un
+// evento semanal, por lo que la regla no puede tener INTERVAL, UNTIL, COUNT ni fechas excluidas; con un rango de
+// fechas, cada ocurrencia dentro del rango se convierte en un evento puntual. La expansión se detiene si ctx es
+// cancelado o supera su tiempo límite
+func expandRecurringEvents(ctx context.Context, events []models.Event, dateRange *models.DateRange) ([]models.Event, error) {
+	var expandedEvents []models.Event
+	for i, event := range events {
+		if event.RRule == "" {
+			expandedEvents = append(expandedEvents, event)
+			continue
+		}
+
+		recurrence, err := parseRecurrenceRule(event.RRule)
+		if err != nil {
+			return nil, err
+		}
+
+		// Sin un rango de fechas no hay semanas concretas en las que aplicar el intervalo, el fin de la recurrencia ni
+		// las fechas excluidas, así que se rechazan en lugar de ocupar todas las semanas
+		if dateRange == nil && (recurrence.interval > 1 || recurrence.until != "" || recurrence.count > 0 || len(event.ExDates) > 0) {
+			return nil, fmt.Errorf("RRULE '%s' with INTERVAL, UNTIL, COUNT or exdates requires the dateRange option", event.RRule)
+		}
+
+		// Sin BYDAY el evento se repite en su propio día de la semana
+		if len(recurrence.byDay) == 0 {
+			day := event.DayOfWeek
+			if day == "" {
+				day = GetDayOfWeekOfDate(event.Date)
+			}
+			recurrence.byDay = []string{day}
+		}
+
+		occurrences := recurrence.byDay
+		if dateRange != nil {
+			occurrences, err = getOccurrenceDates(ctx, event, recurrence, *dateRange)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Cada ocurrencia conserva los demás campos del evento, como su nivel de ocupación, y una referencia al
+		// evento de la solicitud
+		for _, occurrence := range occurrences {
+			expandedEvent := event
+			expandedEvent.DayOfWeek, expandedEvent.Date = describeDay(occurrence)
+			expandedEvent.RRule, expandedEvent.ExDates = "", nil
+			expandedEvent.RecurringEvent = &events[i]
+			expandedEvents = append(expandedEvents, expandedEvent)
+		}
+	}
+
+	return expandedEvents, nil
+}
+
+// Función para obtener las fechas de las ocurrencias de un evento recurrente que afectan al rango de fechas,
+// incluyendo la del día anterior al rango por si el evento cruza la medianoche
+func getOccurrenceDates(ctx context.Context, event models.Event, recurrence recurrenceRule, dateRange models.DateRange) ([]string, error) {
+	// La fecha del evento es la primera posible ocurrencia (DTSTART), si no tiene se usa el inicio del rango
+	start := event.Date
+	if start == "" {
+		start = dateRange.From
+	}
+	startDate, err := time.Parse(DateLayout, start)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid date '%s' for RRULE '%s'", start, event.RRule)
+	}
+
+	// Fechas excluidas de la recurrencia (EXDATE)
+	excludedDates := make(map[string]bool, len(event.ExDates))
+	for _, exDate := range event.ExDates {
+		if !isDate(exDate) {
+			return nil, fmt.Errorf("Invalid exdate '%s' for RRULE '%s'", exDate, event.RRule)
+		}
+		excludedDates[exDate] = true
+	}
+
+	// Lunes de la semana de la primera ocurrencia, a partir del cual se cuentan los intervalos
+	firstMonday := startDate.AddDate(0, 0, -dayIndex(GetDayOfWeekOfDate(start)))
+
+	// Saltar a la primera semana del intervalo que llega al día anterior al rango, contando las ocurrencias
+	// anteriores para COUNT sin recorrerlas día por día
+	date, count := startDate, 0
+	rangeStart, _ := time.Parse(DateLayout, shiftDate(dateRange.From, -1))
+	if weeks := daysBetween(firstMonday, rangeStart) / 7; weeks > 0 {
+		firstWeek := weeks / recurrence.interval * recurrence.interval
+		if firstWeek < weeks {
+			firstWeek += recurrence.interval
+		}
+		rangeEnd, _ := time.Parse(DateLayout, dateRange.To)
+		if firstWeek > daysBetween(firstMonday, rangeEnd)/7 {
+			return nil, nil
+		}
+
+		days := uniqueDays(recurrence.byDay)
+		for _, day := range days {
+			if dayIndex(day) >= dayIndex(GetDayOfWeekOfDate(start)) {
+				count++
+			}
+		}
+		count += (firstWeek/recurrence.interval - 1) * len(days)
+		date = firstMonday.AddDate(0, 0, firstWeek*7)
+	}
+
+	var occurrences []string
+	for ; date.Format(DateLayout) <= dateRange.To; date = date.AddDate(0, 0, 1) {
+		if err := checkContext(ctx); err != nil {
+			return nil, err
+		}
+
+		dateString := date.Format(DateLayout)
+		if recurrence.until != "" && dateString > recurrence.until {
+			break
+		}
+
+		// Verificar que la semana corresponda al intervalo y que el día esté en la regla
+		week := daysBetween(firstMonday, date) / 7
+		if week%recurrence.interval != 0 || !containsDay(recurrence.byDay, GetDayOfWeekOfDate(dateString)) {
+			continue
+		}
+
+		// Las fechas excluidas cuentan para COUNT, como en iCalendar
+		count++
+		if recurrence.count > 0 && count > recurrence.count {
+			break
+		}
+		if !excludedDates[dateString] && dateString >= shiftDate(dateRange.From, -1) {
+			occurrences = append(occurrences, dateString)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// Función auxiliar para contar los días entre dos fechas a medianoche, sin pasar por time.Duration, que solo
+// cubre unos 290 años
+func daysBetween(from, to time.Time) int {
+	return int((to.Unix() - from.Unix()) / 86400)
+}
+
+// Función auxiliar para obtener los días de la semana de una lista sin repetir
+func uniqueDays(days []string) []string {
+	var unique []string
+	for _, day := range days {
+		if !containsDay(unique, day) {
+			unique = append(unique, day)
+		}
+	}
+	return unique
+}
+
+// Función auxiliar para verificar si un día de la semana está en una lista
+func containsDay(days []string, day string) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
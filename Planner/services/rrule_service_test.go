@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	// Caso de prueba: regla completa con prefijo y fecha y hora en UNTIL
+	expectedRule := recurrenceRule{interval: 2, byDay: []string{"l", "i"}, until: "2024-05-31"}
+	actualRule, err := parseRecurrenceRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20240531T235959Z")
+	if err != nil || !reflect.DeepEqual(actualRule, expectedRule) {
+		t.Errorf("ERROR Expected: %v, Got: %v (%v)", expectedRule, actualRule, err)
+	}
+
+	// Caso de prueba: reglas no soportadas o inválidas
+	for _, rule := range []string{"FREQ=DAILY", "FREQ=WEEKLY;COUNT=2;UNTIL=20240531", "FREQ=WEEKLY;BYDAY=XX", "FREQ=WEEKLY;BYMONTH=5", "FREQ=WEEKLY;INTERVAL=0"} {
+		if _, err := parseRecurrenceRule(rule); err == nil {
+			t.Errorf("ERROR Expected an error for '%s'", rule)
+		}
+	}
+}
+
+func TestExpandRecurringEvents(t *testing.T) {
+	// Caso de prueba: laboratorio cada dos semanas los lunes y miércoles desde el 6 de mayo, sin el 8 de mayo
+	events := []models.Event{
+		{Date: "2024-05-06", StartTime: "1400", EndTime: "1600", RRule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", ExDates: []string{"2024-05-08"}},
+		{DayOfWeek: "v", StartTime: "0800", EndTime: "0900"},
+	}
+
+	// Con rango de fechas solo quedan las ocurrencias del rango (y del día anterior)
+	expectedEvents := []models.Event{
+		{DayOfWeek: "l", Date: "2024-05-20", StartTime: "1400", EndTime: "1600", RecurringEvent: &events[0]},
+		{DayOfWeek: "i", Date: "2024-05-22", StartTime: "1400", EndTime: "1600", RecurringEvent: &events[0]},
+		{DayOfWeek: "v", StartTime: "0800", EndTime: "0900"},
+	}
+	assertExpandedEvents(t, events, &models.DateRange{From: "2024-05-08", To: "2024-05-31"}, expectedEvents)
+
+	// Caso de prueba: curso que termina a mitad de semestre por COUNT, sin fecha de inicio explícita
+	events = []models.Event{
+		{DayOfWeek: "m", StartTime: "1000", EndTime: "1200", RRule: "FREQ=WEEKLY;COUNT=2"},
+	}
+	expectedEvents = []models.Event{
+		{DayOfWeek: "m", Date: "2024-05-14", StartTime: "1000", EndTime: "1200", RecurringEvent: &events[0]},
+		{DayOfWeek: "m", Date: "2024-05-21", StartTime: "1000", EndTime: "1200", RecurringEvent: &events[0]},
+	}
+	assertExpandedEvents(t, events, &models.DateRange{From: "2024-05-13", To: "2024-06-30"}, expectedEvents)
+}
+
+func TestExpandRecurringEventsWithoutDateRange(t *testing.T) {
+	// Caso de prueba: sin rango de fechas cada día de la regla se convierte en un evento semanal
+	events := []models.Event{
+		{Date: "2024-05-06", StartTime: "1400", EndTime: "1600", RRule: "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,WE"},
+		{DayOfWeek: "v", StartTime: "0800", EndTime: "0900"},
+	}
+	expectedEvents := []models.Event{
+		{DayOfWeek: "l", StartTime: "1400", EndTime: "1600", RecurringEvent: &events[0]},
+		{DayOfWeek: "i", StartTime: "1400", EndTime: "1600", RecurringEvent: &events[0]},
+		{DayOfWeek: "v", StartTime: "0800", EndTime: "0900"},
+	}
+	assertExpandedEvents(t, events, nil, expectedEvents)
+
+	// Caso de prueba: las reglas que no ocurren todas las semanas requieren un rango de fechas
+	for _, event := range []models.Event{
+		{DayOfWeek: "l", StartTime: "1400", EndTime: "1600", RRule: "FREQ=WEEKLY;BYDAY=MO;INTERVAL=2"},
+		{DayOfWeek: "l", StartTime: "1400", EndTime: "1600", RRule: "FREQ=WEEKLY;BYDAY=MO;COUNT=1"},
+		{DayOfWeek: "l", StartTime: "1400", EndTime: "1600", RRule: "FREQ=WEEKLY;BYDAY=MO;UNTIL=20240531"},
+		{Date: "2024-05-06", StartTime: "1400", EndTime: "1600", RRule: "FREQ=WEEKLY", ExDates: []string{"2024-05-13"}},
+	} {
+		if _, err := expandRecurringEvents(context.Background(), []models.Event{event}, nil); err == nil {
+			t.Errorf("ERROR Expected an error for '%s' without dateRange", event.RRule)
+		}
+	}
+}
+
+func TestBuildSchedulesWithRecurringTentativeEvent(t *testing.T) {
+	// Caso de prueba: un gimnasio tentativo cada lunes, con y sin rango de fechas
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {{DayOfWeek: "l", StartTime: "0700", EndTime: "0759", Availability: "tentative", RRule: "FREQ=WEEKLY"}},
+		},
+	}
+
+	// Las ocurrencias conservan el nivel de ocupación del evento
+	expectedBlocks := []models.TimeBlock{{StartMinute: 420, EndMinute: 479, Tentative: true}}
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(schedules[0].Monday, expectedBlocks) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedBlocks, schedules[0].Monday)
+	}
+
+	request.Options.DateRange = &models.DateRange{From: "2024-05-13", To: "2024-05-14"}
+	schedules, err = BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	if blocks := schedules[0].Dates["2024-05-13"]; !reflect.DeepEqual(blocks, expectedBlocks) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedBlocks, blocks)
+	}
+}
+
+func TestExpandRecurringEventsFarBeforeDateRange(t *testing.T) {
+	// Caso de prueba: una regla que empieza miles de años antes del rango salta a la semana del rango
+	events := []models.Event{
+		{Date: "0001-01-01", StartTime: "1400", EndTime: "1600", RRule: "FREQ=WEEKLY;BYDAY=MO"},
+	}
+	expectedEvents := []models.Event{
+		{DayOfWeek: "l", Date: "9999-12-06", StartTime: "1400", EndTime: "1600", RecurringEvent: &events[0]},
+	}
+	assertExpandedEvents(t, events, &models.DateRange{From: "9999-12-01", To: "9999-12-07"}, expectedEvents)
+
+	// Caso de prueba: las ocurrencias saltadas cuentan para COUNT. Desde el miércoles 3 de enero, cada dos semanas
+	// los lunes y miércoles, la sexta ocurrencia es el lunes 12 de febrero
+	events = []models.Event{
+		{Date: "2024-01-03", StartTime: "1400", EndTime: "1600", RRule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=6"},
+	}
+	expectedEvents = []models.Event{
+		{DayOfWeek: "l", Date: "2024-02-12", StartTime: "1400", EndTime: "1600", RecurringEvent: &events[0]},
+	}
+	assertExpandedEvents(t, events, &models.DateRange{From: "2024-02-12", To: "2024-02-18"}, expectedEvents)
+
+	// Y con COUNT=5 la regla termina antes del rango
+	events[0].RRule = "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=5"
+	assertExpandedEvents(t, events, &models.DateRange{From: "2024-02-12", To: "2024-02-18"}, nil)
+
+	// Caso de prueba: la expansión se detiene al superar el tiempo límite
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	var timeoutErr *TimeoutError
+	if _, err := expandRecurringEvents(ctx, events, &models.DateRange{From: "2024-02-12", To: "2024-02-18"}); !errors.As(err, &timeoutErr) {
+		t.Errorf("ERROR Expected: %v, Got: %v", "a timeout error", err)
+	}
+}
+
+// Función auxiliar para aserciones de los eventos expandidos
+func assertExpandedEvents(t *testing.T, events []models.Event, dateRange *models.DateRange, expectedEvents []models.Event) {
+	actualEvents, err := expandRecurringEvents(context.Background(), events, dateRange)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(actualEvents, expectedEvents) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedEvents, actualEvents)
+	} else {
+		t.Logf("SUCCESS Expected: %v, Got: %v", expectedEvents, actualEvents)
+	}
+}
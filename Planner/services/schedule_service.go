@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"Planner/models"
+)
+
+// BuildSchedules convierte los eventos de cada usuario de la solicitud en su ScheduleModel. La expansión de los
+// eventos recurrentes se detiene si ctx es cancelado o supera su tiempo límite
+func BuildSchedules(ctx context.Context, request models.PlannerRequest) ([]models.ScheduleModel, error) {
+	// Registrar el origen de cada bloque si se pide explicar los conflictos o si se verifica una propuesta, que
+	// siempre los reporta
+	explain := request.Options.Explain || request.Options.Proposal != nil
+
+	// Identificar los usuarios obligatorios de la solicitud
+	requiredUsers := make(map[string]bool, len(request.Required))
+	for _, userID := range request.Required {
+		requiredUsers[userID] = true
+	}
+
+	// Recorrer los usuarios en orden para que los horarios resultantes sean deterministas
+	usersId := make([]string, 0, len(request.Users))
+	for userID := range request.Users {
+		usersId = append(usersId, userID)
+	}
+	sort.Strings(usersId)
+
+	var schedules []models.ScheduleModel
+	for _, userID := range usersId {
+		// Expandir los eventos recurrentes sobre el horizonte de la solicitud
+		events, err := expandRecurringEvents(ctx, request.Users[userID], request.Options.DateRange)
+		if err != nil {
+			return nil, err
+		}
+
+		// Crear ScheduleModel para el usuario actual
+		schedule := models.ScheduleModel{
+			ID:       userID,
+			Required: requiredUsers[userID],
+		}
+
+		// Convertir los eventos semanales a TimeBlock y agregarlos al día correspondiente del ScheduleModel
+		for _, event := range events {
+			if event.Date != "" {
+				continue
+			}
+			block, err := convertToBusyBlock(event, explain)
+			if err != nil {
+				return nil, err
+			}
+			dayBlocks, err := getDayTimeBlocks(&schedule, event.DayOfWeek)
+			if err != nil {
+				return nil, err
+			}
+			*dayBlocks = append(*dayBlocks, block)
+		}
+
+		// Calcular los TimeBlocks de cada fecha si la solicitud planifica sobre un rango de fechas
+		if request.Options.DateRange != nil {
+			if err := addDateTimeBlocks(&schedule, events, getDatesInRange(*request.Options.DateRange), explain); err != nil {
+				return nil, err
+			}
+		}
+
+		// Usar el horario laboral del usuario o, si no tiene uno, el horario laboral por defecto
+		workingHours, ok := request.WorkingHours.Users[userID]
+		if !ok {
+			workingHours = request.WorkingHours.Default
+		}
+		if err := addOutsideWorkingHours(&schedule, workingHours, newWindowSource(models.ReasonOutsideWorkingHours, explain)); err != nil {
+			return nil, err
+		}
+
+		// Si el usuario declara cuándo está disponible, marcar como ocupado todo el tiempo fuera de esas ventanas
+		if availableWindows, ok := request.Available[userID]; ok {
+			if err := addOutsideWindows(&schedule, availableWindows, newWindowSource(models.ReasonOutsideAvailability, explain)); err != nil {
+				return nil, err
+			}
+		}
+
+		// Usar el margen de tiempo del usuario o, si no tiene uno, el margen por defecto. Se asigna al final
+		// porque addDateTimeBlocks normaliza el horario semanal y el margen solo se debe aplicar una vez
+		buffer, ok := request.Options.UserBuffers[userID]
+		if !ok {
+			buffer = request.Options.Buffer
+		}
+		schedule.BufferBefore = buffer.Before
+		schedule.BufferAfter = buffer.After
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+// Función para marcar como ocupado todo el tiempo fuera del horario laboral de un usuario. Los días sin
+// ninguna ventana quedan ocupados por completo y un horario laboral vacío no restringe al usuario
+func addOutsideWorkingHours(schedule *models.ScheduleModel, workingHours []models.Event, source *models.BlockSource) error {
+	if len(workingHours) == 0 {
+		return nil
+	}
+	return addOutsideWindows(schedule, workingHours, source)
+}
+
+// Función auxiliar para crear el origen de los bloques fuera de unas ventanas en el modo de explicación, o nil
+// si la solicitud no lo pide
+func newWindowSource(reason string, explain bool) *models.BlockSource {
+	if !explain {
+		return nil
+	}
+	return &models.BlockSource{Reason: reason}
+}
+
+// Función para marcar como ocupado todo el tiempo fuera de unas ventanas semanales, como el horario laboral o
+// la disponibilidad declarada por un usuario. Los días sin ninguna ventana quedan ocupados por completo. Los
+// bloques agregados se marcan para que no reciban margen y, si source no es nil, lo registran como su origen
+func addOutsideWindows(schedule *models.ScheduleModel, windows []models.Event, source *models.BlockSource) error {
+	// Agrupar las ventanas por día de la semana
+	windowsByDay := make(map[string][]models.TimeBlock)
+	for _, window := range windows {
+		block, err := convertToTimeBlock(window)
+		if err != nil {
+			return err
+		}
+		windowsByDay[window.DayOfWeek] = append(windowsByDay[window.DayOfWeek], block)
+	}
+
+	// Dividir las ventanas que cruzan la medianoche antes de calcular su complemento
+	windowsByDay = splitBlocksAtMidnight(windowsByDay, weekDays, true)
+
+	// Agregar como ocupado el complemento de las ventanas de cada día
+	outsideByDay := make(map[string][]models.TimeBlock)
+	for _, day := range weekDays {
+		dayBlocks, err := getDayTimeBlocks(schedule, day)
+		if err != nil {
+			return err
+		}
+		outsideByDay[day] = invertBlocks(windowsByDay[day])
+		for i := range outsideByDay[day] {
+			outsideByDay[day][i].Outside = true
+			if source != nil {
+				outsideByDay[day][i].Sources = []*models.BlockSource{source}
+			}
+		}
+		*dayBlocks = append(*dayBlocks, outsideByDay[day]...)
+	}
+
+	// Aplicar las mismas ventanas a cada fecha según su día de la semana
+	for date := range schedule.Dates {
+		schedule.Dates[date] = append(schedule.Dates[date], outsideByDay[GetDayOfWeekOfDate(date)]...)
+	}
+
+	return nil
+}
+
+// Función para obtener el complemento de un conjunto de TimeBlocks dentro de un día
+func invertBlocks(timeBlocks []models.TimeBlock) []models.TimeBlock {
+	// Fusionar una copia de los bloques para no modificar el arreglo original
+	sortedBlocks := mergeBlocks(append([]models.TimeBlock{}, timeBlocks...))
+
+	var invertedBlocks []models.TimeBlock
+	nextFreeMinute := 0
+	for _, block := range sortedBlocks {
+		if block.StartMinute > nextFreeMinute {
+			invertedBlocks = append(invertedBlocks, models.TimeBlock{StartMinute: nextFreeMinute, EndMinute: block.StartMinute - 1})
+		}
+		nextFreeMinute = max(nextFreeMinute, block.EndMinute+1)
+	}
+	if nextFreeMinute < 1440 {
+		invertedBlocks = append(invertedBlocks, models.TimeBlock{StartMinute: nextFreeMinute, EndMinute: 1440 - 1})
+	}
+
+	return invertedBlocks
+}
+
+// Función para convertir un evento en un TimeBlock con la hora de inicio y fin en minutos. Si el evento cruza
+// la medianoche, el minuto de fin supera 1440 y normalizeSchedule traslada el exceso al día siguiente
+func convertToTimeBlock(event models.Event) (models.TimeBlock, error) {
+	startMinute, err := convertToMinutes(event.StartTime)
+	if err != nil {
+		return models.TimeBlock{}, err
+	}
+	endMinute, err := convertToMinutes(event.EndTime)
+	if err != nil {
+		return models.TimeBlock{}, err
+	}
+
+	// Un evento que termina antes de su inicio continúa hasta el día siguiente
+	if endMinute < startMinute {
+		endMinute += 1440
+	}
+
+	return models.TimeBlock{StartMinute: startMinute, EndMinute: endMinute, Tentative: event.Availability == "tentative"}, nil
+}
+
+// Función para convertir un evento de un usuario en un TimeBlock ocupado. En el modo de explicación, el bloque
+// registra el evento como su origen, o el evento recurrente de la solicitud si es una de sus ocurrencias
+func convertToBusyBlock(event models.Event, explain bool) (models.TimeBlock, error) {
+	block, err := convertToTimeBlock(event)
+	if err != nil {
+		return models.TimeBlock{}, err
+	}
+	if explain {
+		source := &event
+		if event.RecurringEvent != nil {
+			source = event.RecurringEvent
+		}
+		block.Sources = []*models.BlockSource{{Reason: models.ReasonEvent, Event: source}}
+	}
+	return block, nil
+}
+
+// Función para convertir una hora en formato "HHMM" a los minutos totales desde la medianoche
+func convertToMinutes(timeString string) (int, error) {
+	if len(timeString) != 4 {
+		return 0, fmt.Errorf("Invalid time format for '%s'", timeString)
+	}
+	hours, err := strconv.Atoi(timeString[:2])
+	if err != nil {
+		return 0, fmt.Errorf("Error when converting hour of '%s' to int", timeString)
+	}
+	minutes, err := strconv.Atoi(timeString[2:])
+	if err != nil {
+		return 0, fmt.Errorf("Error when converting minute of '%s' to int", timeString)
+	}
+	return (60 * hours) + minutes, nil
+}
+
+// Función para obtener una referencia a los TimeBlocks de un día del ScheduleModel
+func getDayTimeBlocks(schedule *models.ScheduleModel, day string) (*[]models.TimeBlock, error) {
+	switch day {
+	case "l":
+		return &schedule.Monday, nil
+	case "m":
+		return &schedule.Tuesday, nil
+	case "i":
+		return &schedule.Wednesday, nil
+	case "j":
+		return &schedule.Thursday, nil
+	case "v":
+		return &schedule.Friday, nil
+	case "s":
+		return &schedule.Saturday, nil
+	case "d":
+		return &schedule.Sunday, nil
+	default:
+		return nil, fmt.Errorf("Invalid day of week '%s'", day)
+	}
+}
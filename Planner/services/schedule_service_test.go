@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestInvertBlocks(t *testing.T) {
+	// Caso de prueba: día sin bloques, todo el día queda libre
+	assertInvertedBlocks(t, nil, []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}})
+
+	// Caso de prueba: bloques desordenados y solapados en medio del día
+	timeBlocks := []models.TimeBlock{
+		{StartMinute: 720, EndMinute: 900},
+		{StartMinute: 420, EndMinute: 600},
+		{StartMinute: 500, EndMinute: 659},
+	}
+	expectedBlocks := []models.TimeBlock{
+		{StartMinute: 0, EndMinute: 419},
+		{StartMinute: 660, EndMinute: 719},
+		{StartMinute: 901, EndMinute: 1439},
+	}
+	assertInvertedBlocks(t, timeBlocks, expectedBlocks)
+
+	// Caso de prueba: bloques que tocan el inicio y el fin del día
+	timeBlocks = []models.TimeBlock{
+		{StartMinute: 0, EndMinute: 59},
+		{StartMinute: 1380, EndMinute: 1439},
+	}
+	expectedBlocks = []models.TimeBlock{
+		{StartMinute: 60, EndMinute: 1379},
+	}
+	assertInvertedBlocks(t, timeBlocks, expectedBlocks)
+}
+
+func TestBuildSchedulesWithWorkingHours(t *testing.T) {
+	// Caso de prueba: un usuario con horario propio y otro con el horario por defecto
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {{DayOfWeek: "l", StartTime: "0800", EndTime: "1000"}},
+			"2": {},
+		},
+		WorkingHours: models.WorkingHours{
+			Default: []models.Event{
+				{DayOfWeek: "l", StartTime: "0700", EndTime: "2100"},
+				{DayOfWeek: "m", StartTime: "0700", EndTime: "2100"},
+				{DayOfWeek: "i", StartTime: "0700", EndTime: "2100"},
+				{DayOfWeek: "j", StartTime: "0700", EndTime: "2100"},
+				{DayOfWeek: "v", StartTime: "0700", EndTime: "2100"},
+				{DayOfWeek: "s", StartTime: "0800", EndTime: "1200"},
+			},
+			Users: map[string][]models.Event{
+				"1": {{DayOfWeek: "l", StartTime: "0900", EndTime: "1800"}},
+			},
+		},
+	}
+
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	// Los bloques fuera del horario laboral quedan marcados para que no reciban margen
+	fullDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439, Outside: true}}
+	weekDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 419, Outside: true}, {StartMinute: 1261, EndMinute: 1439, Outside: true}}
+	expectedSchedules := []models.ScheduleModel{
+		{
+			ID: "1",
+			Monday: []models.TimeBlock{
+				{StartMinute: 480, EndMinute: 600},
+				{StartMinute: 0, EndMinute: 539, Outside: true},
+				{StartMinute: 1081, EndMinute: 1439, Outside: true},
+			},
+			Tuesday:   fullDay,
+			Wednesday: fullDay,
+			Thursday:  fullDay,
+			Friday:    fullDay,
+			Saturday:  fullDay,
+			Sunday:    fullDay,
+		},
+		{
+			ID:        "2",
+			Monday:    weekDay,
+			Tuesday:   weekDay,
+			Wednesday: weekDay,
+			Thursday:  weekDay,
+			Friday:    weekDay,
+			Saturday:  []models.TimeBlock{{StartMinute: 0, EndMinute: 479, Outside: true}, {StartMinute: 721, EndMinute: 1439, Outside: true}},
+			Sunday:    fullDay,
+		},
+	}
+	if !reflect.DeepEqual(schedules, expectedSchedules) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSchedules, schedules)
+	}
+
+	// Los bloques fuera del horario se fusionan con los eventos al normalizar el horario
+	expectedMonday := []models.TimeBlock{{StartMinute: 0, EndMinute: 600}, {StartMinute: 1081, EndMinute: 1439}}
+	if monday := normalizeSchedule(schedules[0]).Monday; !reflect.DeepEqual(monday, expectedMonday) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedMonday, monday)
+	}
+}
+
+func TestBuildSchedulesWithOvernightEvents(t *testing.T) {
+	// Caso de prueba: un turno nocturno y una ventana laboral del domingo que continúa el lunes
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {{DayOfWeek: "v", StartTime: "2200", EndTime: "0100"}},
+		},
+		WorkingHours: models.WorkingHours{
+			Default: []models.Event{{DayOfWeek: "d", StartTime: "1800", EndTime: "0600"}},
+		},
+	}
+
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	// El turno queda en el viernes y se divide al normalizar, la ventana se divide antes de invertirla
+	fullDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439, Outside: true}}
+	expectedSchedule := models.ScheduleModel{
+		ID:        "1",
+		Monday:    []models.TimeBlock{{StartMinute: 361, EndMinute: 1439, Outside: true}},
+		Tuesday:   fullDay,
+		Wednesday: fullDay,
+		Thursday:  fullDay,
+		Friday:    []models.TimeBlock{{StartMinute: 1320, EndMinute: 1440 + 60}, {StartMinute: 0, EndMinute: 1439, Outside: true}},
+		Saturday:  fullDay,
+		Sunday:    []models.TimeBlock{{StartMinute: 0, EndMinute: 1079, Outside: true}},
+	}
+	if !reflect.DeepEqual(schedules[0], expectedSchedule) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSchedule, schedules[0])
+	}
+}
+
+func TestBuildSchedulesWithAvailableWindows(t *testing.T) {
+	// Caso de prueba: un usuario con eventos ocupados, otro que declara su disponibilidad y otro que no declara ninguna ventana
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {{DayOfWeek: "l", StartTime: "0800", EndTime: "1059"}},
+			"2": {},
+			"3": {},
+		},
+		Available: map[string][]models.Event{
+			"2": {{DayOfWeek: "l", StartTime: "1000", EndTime: "1359"}},
+			"3": {},
+		},
+	}
+
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	// El usuario 2 está ocupado fuera de su ventana del lunes y el usuario 3 está ocupado toda la semana
+	fullDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439, Outside: true}}
+	if expectedBlocks := []models.TimeBlock{{StartMinute: 0, EndMinute: 599, Outside: true}, {StartMinute: 840, EndMinute: 1439, Outside: true}}; !reflect.DeepEqual(schedules[1].Monday, expectedBlocks) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedBlocks, schedules[1].Monday)
+	}
+	if !reflect.DeepEqual(schedules[1].Tuesday, fullDay) || !reflect.DeepEqual(schedules[2].Monday, fullDay) {
+		t.Errorf("ERROR Expected full busy days, Got: %v and %v", schedules[1].Tuesday, schedules[2].Monday)
+	}
+
+	// Los usuarios 1 y 2 solo coinciden el lunes de 11:00 a 14:00
+	expectedSlots := []models.PlannerEvent{
+		{
+			DayOfWeek:       "l",
+			StartTime:       "11:00",
+			EndTime:         "13:59",
+			UsersAvailable:  2,
+			Attendees:       []string{"1", "2"},
+			Duration:        180,
+			LatestStartTime: "13:00",
+		},
+	}
+	actualSlots := mustGetAvailableTimeSlots(t, schedules[:2], models.PlannerOptions{Duration: 60})
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+}
+
+func TestBuildSchedulesWithBufferAndWorkingHours(t *testing.T) {
+	// Caso de prueba: un usuario sin eventos y otro con un evento, ambos con horario laboral de 08:00 a 17:00 y margen
+	request := models.PlannerRequest{
+		Users: map[string][]models.Event{
+			"1": {},
+			"2": {{DayOfWeek: "l", StartTime: "1200", EndTime: "1259"}},
+		},
+		WorkingHours: models.WorkingHours{
+			Default: []models.Event{{DayOfWeek: "l", StartTime: "0800", EndTime: "1659"}},
+		},
+		Options: models.PlannerOptions{Buffer: models.Buffer{Before: 15, After: 15}},
+	}
+
+	schedules, err := BuildSchedules(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	// El margen solo rodea al evento, así que el horario laboral conserva sus límites
+	expectedMondays := [][]models.TimeBlock{
+		{{StartMinute: 0, EndMinute: 479}, {StartMinute: 1020, EndMinute: 1439}},
+		{{StartMinute: 0, EndMinute: 479}, {StartMinute: 705, EndMinute: 794}, {StartMinute: 1020, EndMinute: 1439}},
+	}
+	for i, expectedMonday := range expectedMondays {
+		if monday := normalizeSchedule(schedules[i]).Monday; !reflect.DeepEqual(monday, expectedMonday) {
+			t.Errorf("ERROR Expected: %v, Got: %v", expectedMonday, monday)
+		}
+	}
+}
+
+// Función auxiliar para aserciones de los bloques invertidos
+func assertInvertedBlocks(t *testing.T, timeBlocks []models.TimeBlock, expectedBlocks []models.TimeBlock) {
+	actualBlocks := invertBlocks(timeBlocks)
+	if !reflect.DeepEqual(actualBlocks, expectedBlocks) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedBlocks, actualBlocks)
+	} else {
+		t.Logf("SUCCESS Expected: %v, Got: %v", expectedBlocks, actualBlocks)
+	}
+}
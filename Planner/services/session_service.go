@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"math/bits"
+
+	"Planner/models"
+)
+
+// FindRecurringSessions elige options.Sessions.Count sesiones semanales de options.Duration minutos, cada una en un
+// día distinto y separadas por al menos options.Sessions.MinDaysApart días (contando del domingo al lunes), que
+// maximizan la suma de asistentes. Retorna una lista vacía si no es posible elegir todas las sesiones
+func FindRecurringSessions(ctx context.Context, schedules []models.ScheduleModel, options models.PlannerOptions) ([]models.PlannerEvent, error) {
+	days, segmentsByDay, err := sweepSchedules(ctx, schedules, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Una sesión necesita a todos los usuarios obligatorios y al menos minAttendees usuarios que, como en /planner,
+	// por defecto son todos los obligatorios o todos los usuarios
+	requiredUsers := getRequiredUsers(schedules)
+	minAttendees := max(getMinAttendees(options, requiredUsers, len(schedules)), 1)
+
+	// Elegir la mejor sesión de cada día: la ventana con más asistentes y, si empatan, la primera
+	bestSessions := make([]*models.PlannerEvent, len(days))
+	for i, day := range days {
+		slots, err := findMeetingSlots(ctx, segmentsByDay[day], minAttendees, options.Duration, options.Granularity, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(requiredUsers) > 0 {
+			slots = applyAttendeeRoles(slots, requiredUsers)
+		}
+		for j := range slots {
+			if bestSessions[i] == nil || slots[j].UsersAvailable > bestSessions[i].UsersAvailable {
+				bestSessions[i] = &slots[j]
+			}
+		}
+	}
+
+	minDaysApart := max(options.Sessions.MinDaysApart, 1)
+	sessions := make([]models.PlannerEvent, 0, options.Sessions.Count)
+	for _, i := range chooseSessionDays(bestSessions, options.Sessions.Count, minDaysApart) {
+		sessions = append(sessions, *bestSessions[i])
+	}
+	return sessions, nil
+}
+
+// Función para elegir los índices de count días de la semana con sesión, separados por al menos minDaysApart
+// días en la semana circular, que maximizan la suma de asistentes. Con siete días basta con probar todas las
+// combinaciones; si varias empatan, se conserva la primera
+func chooseSessionDays(bestSessions []*models.PlannerEvent, count int, minDaysApart int) []int {
+	n := len(bestSessions)
+	bestMask, bestAttendance := -1, -1
+
+	for mask := 0; mask < 1<<n; mask++ {
+		if bits.OnesCount(uint(mask)) != count {
+			continue
+		}
+
+		// Descartar las combinaciones con días sin sesión o demasiado cercanos
+		attendance, valid := 0, true
+		for i := 0; i < n && valid; i++ {
+			if mask&(1<<i) == 0 {
+				continue
+			}
+			if bestSessions[i] == nil {
+				valid = false
+				break
+			}
+			attendance += bestSessions[i].UsersAvailable
+
+			for j := i + 1; j < n; j++ {
+				if mask&(1<<j) != 0 && min(j-i, n-(j-i)) < minDaysApart {
+					valid = false
+					break
+				}
+			}
+		}
+
+		if valid && attendance > bestAttendance {
+			bestMask, bestAttendance = mask, attendance
+		}
+	}
+
+	var chosenDays []int
+	for i := 0; i < n && bestMask >= 0; i++ {
+		if bestMask&(1<<i) != 0 {
+			chosenDays = append(chosenDays, i)
+		}
+	}
+	return chosenDays
+}
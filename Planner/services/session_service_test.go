@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestFindRecurringSessions(t *testing.T) {
+	// Caso de prueba: tres usuarios libres de 10:00 a 11:00 en distintos días, el viernes y el sábado nadie está libre
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	freeHour := []models.TimeBlock{{StartMinute: 0, EndMinute: 599}, {StartMinute: 660, EndMinute: 1439}}
+	newSchedules := func() []models.ScheduleModel {
+		return []models.ScheduleModel{
+			{ID: "1", Monday: freeHour, Tuesday: freeHour, Wednesday: freeHour, Thursday: freeHour, Friday: busyDay, Saturday: busyDay, Sunday: freeHour},
+			{ID: "2", Monday: freeHour, Tuesday: freeHour, Wednesday: busyDay, Thursday: freeHour, Friday: busyDay, Saturday: busyDay, Sunday: freeHour},
+			{ID: "3", Monday: freeHour, Tuesday: freeHour, Wednesday: busyDay, Thursday: busyDay, Friday: busyDay, Saturday: busyDay, Sunday: freeHour},
+		}
+	}
+
+	// Dos sesiones separadas por dos días: el lunes y el domingo están a un día de distancia, así que se elige martes y domingo
+	options := models.PlannerOptions{Duration: 60, MinAttendees: 1, Sessions: &models.SessionOptions{Count: 2, MinDaysApart: 2}}
+	assertSessions(t, newSchedules(), options, []string{"m", "d"}, []int{3, 3})
+
+	// Tres sesiones separadas por dos días: martes, jueves y domingo
+	options.Sessions.Count = 3
+	assertSessions(t, newSchedules(), options, []string{"m", "j", "d"}, []int{3, 2, 3})
+
+	// Tres sesiones separadas por tres días no caben en una semana
+	options.Sessions.MinDaysApart = 3
+	assertSessions(t, newSchedules(), options, []string{}, []int{})
+
+	// Con el usuario 3 obligatorio solo quedan el lunes, el martes y el domingo, donde no caben tres sesiones
+	options.Sessions = &models.SessionOptions{Count: 3, MinDaysApart: 2}
+	schedules := newSchedules()
+	schedules[2].Required = true
+	assertSessions(t, schedules, options, []string{}, []int{})
+
+	// Pero sí caben dos sesiones
+	options.Sessions.Count = 2
+	schedules = newSchedules()
+	schedules[2].Required = true
+	assertSessions(t, schedules, options, []string{"m", "d"}, []int{3, 3})
+
+	// Sin minAttendees, como en /planner, cada sesión necesita a todos los usuarios y el jueves ya no sirve
+	options = models.PlannerOptions{Duration: 60, Sessions: &models.SessionOptions{Count: 3, MinDaysApart: 2}}
+	assertSessions(t, newSchedules(), options, []string{}, []int{})
+	options.Sessions.Count = 2
+	assertSessions(t, newSchedules(), options, []string{"m", "d"}, []int{3, 3})
+}
+
+// Función auxiliar para aserciones de los días y asistentes de las sesiones
+func assertSessions(t *testing.T, schedules []models.ScheduleModel, options models.PlannerOptions, expectedDays []string, expectedAttendance []int) {
+	sessions, err := FindRecurringSessions(context.Background(), schedules, options)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+
+	actualDays, actualAttendance := []string{}, []int{}
+	for _, session := range sessions {
+		actualDays = append(actualDays, session.DayOfWeek)
+		actualAttendance = append(actualAttendance, session.UsersAvailable)
+	}
+	if !reflect.DeepEqual(actualDays, expectedDays) || !reflect.DeepEqual(actualAttendance, expectedAttendance) {
+		t.Errorf("ERROR Expected: %v %v, Got: %v %v", expectedDays, expectedAttendance, actualDays, actualAttendance)
+	} else {
+		t.Logf("SUCCESS Expected: %v %v, Got: %v %v", expectedDays, expectedAttendance, actualDays, actualAttendance)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"Planner/models"
+)
+
+// Número de subconjuntos a retornar por defecto y número máximo de subconjuntos candidatos a evaluar
+const defaultSubsetsTop = 5
+const maxSubsetCandidates = 1000
+
+// FindAttendeeSubsets obtiene los grupos de al menos options.Subsets.Size usuarios que pueden reunirse, junto con
+// los slots donde cada grupo está libre. Se prefieren los grupos que coinciden en más slots, luego los más grandes
+// y luego los que suman más minutos disponibles
+func FindAttendeeSubsets(ctx context.Context, schedules []models.ScheduleModel, options models.PlannerOptions) ([]models.AttendeeSubset, error) {
+	size := options.Subsets.Size
+
+	// Obtener las ventanas donde al menos size usuarios están libres durante la duración solicitada
+	slotOptions := options
+	slotOptions.MinAttendees = size
+	slotOptions.Rank = nil
+	slots, err := GetAvailableTimeSlots(ctx, schedules, slotOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Representar los asistentes de cada ventana como conjuntos sobre la tabla de IDs ordenados de los usuarios
+	usersId := make([]string, len(schedules))
+	for i, schedule := range schedules {
+		usersId[i] = schedule.ID
+	}
+	table := newUserTable(usersId)
+	slotSets := make([]userSet, len(slots))
+	for i, slot := range slots {
+		slotSets[i] = newUserSetOf(table, slot.Attendees)
+	}
+
+	// Los grupos candidatos son los asistentes de cada ventana y sus intersecciones de al menos size usuarios,
+	// es decir, los grupos más grandes que coinciden en un mismo conjunto de ventanas
+	var candidates []userSet
+	seen := make(map[string]bool)
+	addCandidate := func(set userSet) {
+		key := fmt.Sprint(set.bits)
+		if set.count() >= size && !seen[key] && len(candidates) < maxSubsetCandidates {
+			seen[key] = true
+			candidates = append(candidates, set)
+		}
+	}
+	for _, set := range slotSets {
+		addCandidate(set)
+	}
+	for i := 0; i < len(candidates); i++ {
+		if err := checkContext(ctx); err != nil {
+			return nil, err
+		}
+		for _, set := range slotSets {
+			addCandidate(candidates[i].intersect(set))
+		}
+	}
+
+	// Asociar a cada grupo las ventanas donde todos sus usuarios están libres. Una ventana contenida en otra donde
+	// el grupo también está libre no es otro momento para reunirse, así que no se cuenta
+	intervals := getSlotIntervals(slots, options)
+	subsets := make([]models.AttendeeSubset, 0, len(candidates))
+	durations := make(map[string]int, len(candidates))
+	for _, set := range candidates {
+		var groupSlots []int
+		for i, slotSet := range slotSets {
+			if set.isSubsetOf(slotSet) {
+				groupSlots = append(groupSlots, i)
+			}
+		}
+
+		attendees := set.toIDs()
+		subset := models.AttendeeSubset{Attendees: attendees, Size: len(attendees)}
+		for _, i := range groupSlots {
+			if !isNestedSlot(i, groupSlots, intervals, options) {
+				subset.Slots = append(subset.Slots, slots[i])
+				durations[strings.Join(attendees, ",")] += slots[i].Duration
+			}
+		}
+		subsets = append(subsets, subset)
+	}
+
+	sort.SliceStable(subsets, func(i, j int) bool {
+		a, b := subsets[i], subsets[j]
+		if len(a.Slots) != len(b.Slots) {
+			return len(a.Slots) > len(b.Slots)
+		}
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		return durations[strings.Join(a.Attendees, ",")] > durations[strings.Join(b.Attendees, ",")]
+	})
+
+	top := options.Subsets.Top
+	if top == 0 {
+		top = defaultSubsetsTop
+	}
+	if len(subsets) > top {
+		subsets = subsets[:top]
+	}
+	return subsets, nil
+}
+
+// Función auxiliar para obtener el inicio y el fin [start, end) de cada slot en minutos, contados desde la
+// medianoche del lunes o, con un rango de fechas, de su primera fecha
+func getSlotIntervals(slots []models.PlannerEvent, options models.PlannerOptions) [][2]int {
+	var dates []string
+	if options.DateRange != nil {
+		dates = getDatesInRange(*options.DateRange)
+	}
+
+	intervals := make([][2]int, len(slots))
+	for i, slot := range slots {
+		day := dayIndex(slot.DayOfWeek)
+		if slot.Date != "" {
+			day = slices.Index(dates, slot.Date)
+		}
+		start, _ := convertToMinutes(strings.Replace(slot.StartTime, ":", "", 1))
+		intervals[i] = [2]int{day*1440 + start, day*1440 + start + slot.Duration}
+	}
+	return intervals
+}
+
+// Función auxiliar para verificar si el slot i está contenido en otro de los slots de un grupo. Con la semana
+// circular, un slot del lunes puede estar contenido en uno que empieza el domingo
+func isNestedSlot(i int, groupSlots []int, intervals [][2]int, options models.PlannerOptions) bool {
+	shifts := []int{0}
+	if options.WeekWrap && options.DateRange == nil {
+		shifts = []int{0, 7 * 1440, -7 * 1440}
+	}
+
+	for _, j := range groupSlots {
+		// Entre dos slots iguales se conserva el primero
+		if j == i || (intervals[j] == intervals[i] && j > i) {
+			continue
+		}
+		for _, shift := range shifts {
+			if intervals[j][0]+shift <= intervals[i][0] && intervals[i][1] <= intervals[j][1]+shift {
+				return true
+			}
+		}
+	}
+	return false
+}
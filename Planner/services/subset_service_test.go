@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestFindAttendeeSubsets(t *testing.T) {
+	// Caso de prueba: cuatro usuarios libres de 10:00 a 11:00 en grupos distintos el lunes, el martes y el miércoles
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	freeHour := []models.TimeBlock{{StartMinute: 0, EndMinute: 599}, {StartMinute: 660, EndMinute: 1439}}
+	newSchedule := func(id string, monday, tuesday, wednesday []models.TimeBlock) models.ScheduleModel {
+		return models.ScheduleModel{
+			ID: id, Monday: monday, Tuesday: tuesday, Wednesday: wednesday,
+			Thursday: busyDay, Friday: busyDay, Saturday: busyDay, Sunday: busyDay,
+		}
+	}
+	schedules := []models.ScheduleModel{
+		newSchedule("1", freeHour, freeHour, busyDay),
+		newSchedule("2", freeHour, freeHour, busyDay),
+		newSchedule("3", freeHour, busyDay, freeHour),
+		newSchedule("4", busyDay, freeHour, freeHour),
+	}
+	options := models.PlannerOptions{Duration: 60, Subsets: &models.SubsetOptions{Size: 2, Top: 3}}
+
+	// Los usuarios 1 y 2 coinciden el lunes y el martes, así que su grupo va primero aunque sea más pequeño
+	subsets, err := FindAttendeeSubsets(context.Background(), schedules, options)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	expectedAttendees := [][]string{{"1", "2"}, {"1", "2", "3"}, {"1", "2", "4"}}
+	expectedDays := [][]string{{"l", "m"}, {"l"}, {"m"}}
+
+	actualAttendees, actualDays := [][]string{}, [][]string{}
+	for _, subset := range subsets {
+		if subset.Size != len(subset.Attendees) {
+			t.Errorf("ERROR Expected size %d, Got: %d", len(subset.Attendees), subset.Size)
+		}
+		actualAttendees = append(actualAttendees, subset.Attendees)
+
+		days := []string{}
+		for _, slot := range subset.Slots {
+			days = append(days, slot.DayOfWeek)
+		}
+		actualDays = append(actualDays, days)
+	}
+	if !reflect.DeepEqual(actualAttendees, expectedAttendees) || !reflect.DeepEqual(actualDays, expectedDays) {
+		t.Errorf("ERROR Expected: %v %v, Got: %v %v", expectedAttendees, expectedDays, actualAttendees, actualDays)
+	}
+
+	// Con grupos de al menos tres usuarios, el miércoles no tiene ningún slot
+	options.Subsets = &models.SubsetOptions{Size: 3}
+	subsets, err = FindAttendeeSubsets(context.Background(), schedules, options)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	if len(subsets) != 2 || !reflect.DeepEqual(subsets[0].Attendees, []string{"1", "2", "3"}) || !reflect.DeepEqual(subsets[1].Attendees, []string{"1", "2", "4"}) {
+		t.Errorf("ERROR Expected the groups 1, 2, 3 and 1, 2, 4, Got: %v", subsets)
+	}
+}
+
+func TestFindAttendeeSubsetsWithNestedSlots(t *testing.T) {
+	// Caso de prueba: los usuarios 1 y 2 libres el lunes de 09:00 a 12:00 y el usuario 3 solo de 10:00 a 11:00
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	newSchedule := func(id string, monday []models.TimeBlock) models.ScheduleModel {
+		return models.ScheduleModel{
+			ID: id, Monday: monday, Tuesday: busyDay, Wednesday: busyDay,
+			Thursday: busyDay, Friday: busyDay, Saturday: busyDay, Sunday: busyDay,
+		}
+	}
+	morning := []models.TimeBlock{{StartMinute: 0, EndMinute: 539}, {StartMinute: 720, EndMinute: 1439}}
+	schedules := []models.ScheduleModel{
+		newSchedule("1", morning),
+		newSchedule("2", morning),
+		newSchedule("3", []models.TimeBlock{{StartMinute: 0, EndMinute: 599}, {StartMinute: 660, EndMinute: 1439}}),
+	}
+	options := models.PlannerOptions{Duration: 60, Subsets: &models.SubsetOptions{Size: 2}}
+
+	// La ventana de los tres usuarios está dentro de la de los usuarios 1 y 2, así que ambos grupos coinciden en
+	// un solo slot y el grupo más grande va primero
+	subsets, err := FindAttendeeSubsets(context.Background(), schedules, options)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	expectedAttendees := [][]string{{"1", "2", "3"}, {"1", "2"}}
+	expectedStartTimes := [][]string{{"10:00"}, {"09:00"}}
+
+	actualAttendees, actualStartTimes := [][]string{}, [][]string{}
+	for _, subset := range subsets {
+		actualAttendees = append(actualAttendees, subset.Attendees)
+
+		startTimes := []string{}
+		for _, slot := range subset.Slots {
+			startTimes = append(startTimes, slot.StartTime)
+		}
+		actualStartTimes = append(actualStartTimes, startTimes)
+	}
+	if !reflect.DeepEqual(actualAttendees, expectedAttendees) || !reflect.DeepEqual(actualStartTimes, expectedStartTimes) {
+		t.Errorf("ERROR Expected: %v %v, Got: %v %v", expectedAttendees, expectedStartTimes, actualAttendees, actualStartTimes)
+	}
+}
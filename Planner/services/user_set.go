@@ -0,0 +1,131 @@
+package services
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// userSet representa un conjunto de usuarios como un bitset sobre una tabla de IDs ordenados: el bit i indica
+// si el usuario ids[i] pertenece al conjunto. Todos los conjuntos de una misma búsqueda usan la misma tabla, por
+// lo que las operaciones entre ellos se resuelven palabra por palabra sin comparar IDs
+type userSet struct {
+	ids  []string // Tabla de IDs ordenados, compartida y de solo lectura
+	bits []uint64
+}
+
+// Función para crear un conjunto vacío sobre la tabla de IDs
+func newUserSet(ids []string) userSet {
+	return userSet{ids: ids, bits: make([]uint64, (len(ids)+63)/64)}
+}
+
+// Función para crear un conjunto con todos los usuarios de la tabla de IDs
+func newFullUserSet(ids []string) userSet {
+	set := newUserSet(ids)
+	for i := range set.bits {
+		set.bits[i] = ^uint64(0)
+	}
+
+	// Limpiar los bits sobrantes de la última palabra
+	if extra := len(ids) % 64; extra != 0 {
+		set.bits[len(set.bits)-1] = (1 << extra) - 1
+	}
+	return set
+}
+
+// Función auxiliar para crear un conjunto de usuarios a partir de una lista de IDs de la tabla
+func newUserSetOf(ids []string, users []string) userSet {
+	set := newUserSet(ids)
+	for _, userID := range users {
+		if i := sort.SearchStrings(ids, userID); i < len(ids) && ids[i] == userID {
+			set.add(i)
+		}
+	}
+	return set
+}
+
+// Función para agregar al usuario con el índice i
+func (s *userSet) add(i int) {
+	s.bits[i/64] |= 1 << (i % 64)
+}
+
+// Función para quitar al usuario con el índice i
+func (s *userSet) remove(i int) {
+	s.bits[i/64] &^= 1 << (i % 64)
+}
+
+// Función para copiar el conjunto, compartiendo la tabla de IDs
+func (s userSet) clone() userSet {
+	return userSet{ids: s.ids, bits: append([]uint64(nil), s.bits...)}
+}
+
+// Función para contar los usuarios del conjunto
+func (s userSet) count() int {
+	count := 0
+	for _, word := range s.bits {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// Función para obtener los usuarios que están en ambos conjuntos
+func (s userSet) intersect(other userSet) userSet {
+	result := userSet{ids: s.ids, bits: make([]uint64, min(len(s.bits), len(other.bits)))}
+	for i := range result.bits {
+		result.bits[i] = s.bits[i] & other.bits[i]
+	}
+	return result
+}
+
+// Función para obtener los usuarios que están en alguno de los dos conjuntos
+func (s userSet) union(other userSet) userSet {
+	if len(s.bits) < len(other.bits) {
+		s, other = other, s
+	}
+	result := s.clone()
+	for i, word := range other.bits {
+		result.bits[i] |= word
+	}
+	return result
+}
+
+// Función para obtener los usuarios del conjunto que no están en el otro
+func (s userSet) difference(other userSet) userSet {
+	result := s.clone()
+	for i := range result.bits {
+		if i < len(other.bits) {
+			result.bits[i] &^= other.bits[i]
+		}
+	}
+	return result
+}
+
+// Función para verificar si todos los usuarios del conjunto están en el otro
+func (s userSet) isSubsetOf(other userSet) bool {
+	for i, word := range s.bits {
+		if i < len(other.bits) {
+			word &^= other.bits[i]
+		}
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Función para verificar si los dos conjuntos tienen los mismos usuarios
+func (s userSet) equal(other userSet) bool {
+	return s.isSubsetOf(other) && other.isSubsetOf(s)
+}
+
+// Función para obtener los IDs de los usuarios del conjunto, ordenados según la tabla
+func (s userSet) toIDs() []string {
+	users := make([]string, 0, s.count())
+	for i, word := range s.bits {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			users = append(users, s.ids[i*64+bit])
+			word &= word - 1
+		}
+	}
+	return users
+}
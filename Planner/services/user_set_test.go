@@ -0,0 +1,60 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUserSet(t *testing.T) {
+	// Tabla de IDs con más de 64 usuarios para cubrir varias palabras del bitset
+	var ids []string
+	for i := 0; i < 70; i++ {
+		ids = append(ids, convertToTimeString(i))
+	}
+
+	// Caso de prueba: un conjunto con todos los usuarios no tiene bits fuera de la tabla
+	full := newFullUserSet(ids)
+	if full.count() != 70 {
+		t.Errorf("ERROR Expected: %v, Got: %v", 70, full.count())
+	}
+	if !reflect.DeepEqual(full.toIDs(), ids) {
+		t.Errorf("ERROR Expected: %v, Got: %v", ids, full.toIDs())
+	}
+
+	// Caso de prueba: los IDs que no están en la tabla se ignoran
+	a := newUserSetOf(ids, []string{ids[0], ids[3], ids[65], "desconocido"})
+	b := newUserSetOf(ids, []string{ids[3], ids[65], ids[69]})
+	if a.count() != 3 {
+		t.Errorf("ERROR Expected: %v, Got: %v", 3, a.count())
+	}
+
+	// Caso de prueba: operaciones entre conjuntos
+	expectedIntersection := []string{ids[3], ids[65]}
+	if actual := a.intersect(b).toIDs(); !reflect.DeepEqual(actual, expectedIntersection) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedIntersection, actual)
+	}
+	expectedUnion := []string{ids[0], ids[3], ids[65], ids[69]}
+	if actual := a.union(b).toIDs(); !reflect.DeepEqual(actual, expectedUnion) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedUnion, actual)
+	}
+	expectedDifference := []string{ids[0]}
+	if actual := a.difference(b).toIDs(); !reflect.DeepEqual(actual, expectedDifference) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedDifference, actual)
+	}
+	if a.isSubsetOf(b) || !a.intersect(b).isSubsetOf(b) || !a.isSubsetOf(full) {
+		t.Errorf("ERROR Expected subset checks to hold for %v and %v", a.toIDs(), b.toIDs())
+	}
+
+	// Caso de prueba: agregar y quitar usuarios no modifica las copias
+	c := a.clone()
+	c.remove(0)
+	c.add(69)
+	if !c.equal(b) || a.equal(b) {
+		t.Errorf("ERROR Expected: %v, Got: %v", b.toIDs(), c.toIDs())
+	}
+
+	// Caso de prueba: un conjunto vacío retorna una lista vacía
+	if actual := newUserSet(ids).toIDs(); actual == nil || len(actual) != 0 {
+		t.Errorf("ERROR Expected: %v, Got: %v", []string{}, actual)
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"Planner/models"
+	"context"
+)
+
+// Función para encontrar las ventanas de reunión de una línea de tiempo y convertirlas en PlannerEvents
+func findMeetingSlots(ctx context.Context, segments []availabilitySegment, minAttendees int, duration int, granularity int, circular bool) ([]models.PlannerEvent, error) {
+	windows, err := findMeetingWindows(ctx, segments, minAttendees, duration, granularity, circular)
+	if err != nil {
+		return nil, err
+	}
+
+	plannerEvents := make([]models.PlannerEvent, 0, len(windows))
+	for _, window := range windows {
+		plannerEvents = append(plannerEvents, newWindowEvent(window, duration, granularity))
+	}
+
+	return plannerEvents, nil
+}
+
+// Función auxiliar para convertir una ventana en un PlannerEvent con la hora de inicio más tardía de una reunión
+// de duration minutos
+func newWindowEvent(window availabilitySegment, duration int, granularity int) models.PlannerEvent {
+	event := newPlannerEvent(window)
+
+	// La reunión puede iniciar en cualquier momento entre el inicio de la ventana y este valor, que también
+	// se alinea a la cuadrícula
+	latestStart := window.end - duration
+	if granularity > 0 {
+		latestStart = floorDiv(latestStart, granularity) * granularity
+	}
+	event.LatestStartTime = convertToTimeString(latestStart % 1440)
+	if latestStartDay := shiftDay(window.day, latestStart/1440); latestStartDay != shiftDay(window.day, window.start/1440) {
+		event.LatestStartDayOfWeek, event.LatestStartDate = describeDay(latestStartDay)
+	}
+
+	return event
+}
+
+// Función para encontrar las ventanas maximales donde un mismo grupo de al menos minAttendees usuarios
+// está libre durante al menos minDuration minutos consecutivos, alineadas a una cuadrícula de granularity minutos.
+// Si la línea de tiempo es circular, las ventanas pueden continuar desde el último segmento hasta el primero
+func findMeetingWindows(ctx context.Context, segments []availabilitySegment, minAttendees int, minDuration int, granularity int, circular bool) ([]availabilitySegment, error) {
+	// Una reunión necesita al menos un asistente
+	minAttendees = max(minAttendees, 1)
+
+	// Extender cada segmento hacia adelante mientras el grupo de usuarios libres siga siendo suficiente
+	var candidates []availabilitySegment
+	for i := range segments {
+		if err := checkContext(ctx); err != nil {
+			return nil, err
+		}
+
+		// En una línea de tiempo circular se puede dar la vuelta completa hasta el segmento anterior a i
+		last := len(segments) - 1
+		if circular {
+			last = i + len(segments) - 1
+		}
+
+		// Rastrear también los usuarios libres o con solo bloques tentativos durante toda la ventana
+		attendees := segments[i].attendees
+		attendeeCount := attendees.count()
+		reachable := segments[i].attendees.union(segments[i].tentative)
+		for j := i; j <= last && attendeeCount >= minAttendees; j++ {
+			// Si el grupo se reduce, la ventana con el grupo anterior termina en el segmento previo
+			segment := segments[j%len(segments)]
+			if !attendees.isSubsetOf(segment.attendees) {
+				if j > i {
+					candidates = append(candidates, newWindow(segments[i], segments[(j-1)%len(segments)], attendees, reachable))
+				}
+				attendees = attendees.intersect(segment.attendees)
+				attendeeCount = attendees.count()
+			}
+			reachable = reachable.intersect(segment.attendees.union(segment.tentative))
+
+			// Si se llega al último segmento, la ventana termina allí
+			if j == last && attendeeCount >= minAttendees {
+				candidates = append(candidates, newWindow(segments[i], segment, attendees, reachable))
+			}
+		}
+	}
+
+	// Alinear las ventanas a la cuadrícula antes de filtrarlas, ya que al reducirse pueden dejar de ser suficientemente largas
+	snappedCandidates := candidates[:0]
+	for _, candidate := range candidates {
+		if candidate, ok := snapSegment(candidate, granularity); ok {
+			snappedCandidates = append(snappedCandidates, candidate)
+		}
+	}
+	candidates = snappedCandidates
+
+	// Conservar solo las ventanas suficientemente largas y que no estén contenidas en otra mejor
+	var windows []availabilitySegment
+	for i, candidate := range candidates {
+		if err := checkContext(ctx); err != nil {
+			return nil, err
+		}
+		if candidate.attendees.count() < minAttendees || candidate.end-candidate.start < minDuration {
+			continue
+		}
+
+		dominated := false
+		for j, other := range candidates {
+			if i == j || !containsWindow(other, candidate, circular) || !candidate.attendees.isSubsetOf(other.attendees) {
+				continue
+			}
+
+			// Si las dos ventanas son equivalentes, solo se conserva la primera
+			equivalent := containsWindow(candidate, other, circular) && other.attendees.isSubsetOf(candidate.attendees)
+			if !equivalent || j < i {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			windows = append(windows, candidate)
+		}
+	}
+
+	return windows, nil
+}
+
+// Función auxiliar para crear una ventana desde el segmento first hasta el segmento last. Los usuarios de
+// reachable que no están en attendees son los tentativos de la ventana
+func newWindow(first, last availabilitySegment, attendees userSet, reachable userSet) availabilitySegment {
+	window := availabilitySegment{
+		day:       first.day,
+		start:     first.start,
+		end:       last.end,
+		attendees: attendees,
+		tentative: reachable.difference(attendees),
+	}
+
+	// En una línea de tiempo circular, la ventana que da la vuelta termina en la semana siguiente
+	for window.end <= window.start {
+		window.end += 7 * 1440
+	}
+
+	return window
+}
+
+// Función auxiliar para verificar si la ventana outer contiene a la ventana inner
+func containsWindow(outer, inner availabilitySegment, circular bool) bool {
+	if !circular {
+		return outer.start <= inner.start && inner.end <= outer.end
+	}
+
+	// Una ventana que cubre la semana completa contiene a cualquier otra
+	if outer.end-outer.start >= 7*1440 {
+		return true
+	}
+
+	// En una línea de tiempo circular se mide el desfase desde el inicio de outer, dando la vuelta a la semana
+	offset := ((inner.start-outer.start)%(7*1440) + 7*1440) % (7 * 1440)
+	return offset+inner.end-inner.start <= outer.end-outer.start
+}
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Planner/models"
+)
+
+func TestFindMeetingSlots(t *testing.T) {
+	// Caso de prueba: ningún segmento es suficientemente largo para la reunión
+	segments := newTestSegments([]testSegment{
+		{day: "l", start: 0, end: 30, attendees: []string{"1", "2"}},
+		{day: "l", start: 30, end: 60, attendees: []string{"1"}},
+		{day: "l", start: 60, end: 1440, attendees: []string{"2"}},
+	})
+	expectedSlots := []models.PlannerEvent{}
+	assertMeetingSlots(t, segments, 2, 60, expectedSlots)
+
+	// Caso de prueba: segmentos contiguos donde el mismo grupo sigue libre forman una sola ventana
+	segments = newTestSegments([]testSegment{
+		{day: "m", start: 0, end: 600, attendees: []string{"1", "2", "3"}},
+		{day: "m", start: 600, end: 660, attendees: []string{"1", "2"}},
+		{day: "m", start: 660, end: 720, attendees: []string{"1", "2", "3"}},
+		{day: "m", start: 720, end: 1440, attendees: []string{"3"}},
+	})
+	expectedSlots = []models.PlannerEvent{
+		{
+			DayOfWeek:       "m",
+			StartTime:       "00:00",
+			EndTime:         "09:59",
+			UsersAvailable:  3,
+			Attendees:       []string{"1", "2", "3"},
+			Duration:        600,
+			LatestStartTime: "08:30",
+		},
+		{
+			DayOfWeek:       "m",
+			StartTime:       "00:00",
+			EndTime:         "11:59",
+			UsersAvailable:  2,
+			Attendees:       []string{"1", "2"},
+			Duration:        720,
+			LatestStartTime: "10:30",
+		},
+	}
+	assertMeetingSlots(t, segments, 2, 90, expectedSlots)
+
+	// Caso de prueba: un grupo grande durante poco tiempo y un grupo pequeño durante más tiempo
+	segments = newTestSegments([]testSegment{
+		{day: "i", start: 0, end: 480, attendees: []string{}},
+		{day: "i", start: 480, end: 540, attendees: []string{"1", "2", "3"}},
+		{day: "i", start: 540, end: 600, attendees: []string{"1", "2"}},
+		{day: "i", start: 600, end: 1440, attendees: []string{}},
+	})
+	expectedSlots = []models.PlannerEvent{
+		{
+			DayOfWeek:       "i",
+			StartTime:       "08:00",
+			EndTime:         "08:59",
+			UsersAvailable:  3,
+			Attendees:       []string{"1", "2", "3"},
+			Duration:        60,
+			LatestStartTime: "08:00",
+		},
+		{
+			DayOfWeek:       "i",
+			StartTime:       "08:00",
+			EndTime:         "09:59",
+			UsersAvailable:  2,
+			Attendees:       []string{"1", "2"},
+			Duration:        120,
+			LatestStartTime: "09:00",
+		},
+	}
+	assertMeetingSlots(t, segments, 2, 60, expectedSlots)
+
+	// Caso de prueba: el mínimo de asistentes descarta la ventana del grupo pequeño
+	expectedSlots = []models.PlannerEvent{
+		{
+			DayOfWeek:       "i",
+			StartTime:       "08:00",
+			EndTime:         "08:59",
+			UsersAvailable:  3,
+			Attendees:       []string{"1", "2", "3"},
+			Duration:        60,
+			LatestStartTime: "08:30",
+		},
+	}
+	assertMeetingSlots(t, segments, 3, 30, expectedSlots)
+
+	// Caso de prueba: la duración descarta la ventana del grupo grande
+	expectedSlots = []models.PlannerEvent{
+		{
+			DayOfWeek:       "i",
+			StartTime:       "08:00",
+			EndTime:         "09:59",
+			UsersAvailable:  2,
+			Attendees:       []string{"1", "2"},
+			Duration:        120,
+			LatestStartTime: "08:30",
+		},
+	}
+	assertMeetingSlots(t, segments, 2, 90, expectedSlots)
+}
+
+func TestGetAvailableTimeSlotsWithDuration(t *testing.T) {
+	// Caso de prueba: dos usuarios con clases el viernes buscando una reunión de dos horas
+	schedules := []models.ScheduleModel{
+		{ID: "1", Friday: []models.TimeBlock{{StartMinute: 0, EndMinute: 479}, {StartMinute: 600, EndMinute: 1439}}},
+		{ID: "2", Friday: []models.TimeBlock{{StartMinute: 0, EndMinute: 539}, {StartMinute: 720, EndMinute: 1439}}},
+	}
+	options := models.PlannerOptions{Duration: 120}
+
+	var fridaySlots []models.PlannerEvent
+	for _, slot := range mustGetAvailableTimeSlots(t, schedules, options) {
+		if slot.DayOfWeek == "v" {
+			fridaySlots = append(fridaySlots, slot)
+		}
+	}
+
+	// Los dos usuarios solo coinciden de 09:00 a 10:00, por lo que no cabe una reunión de dos horas
+	if len(fridaySlots) != 0 {
+		t.Errorf("ERROR Expected no slots on friday, Got: %v", fridaySlots)
+	}
+
+	// Con un solo asistente, el usuario 1 tiene libre de 08:00 a 10:00 y el usuario 2 de 09:00 a 12:00
+	options.MinAttendees = 1
+	fridaySlots = nil
+	for _, slot := range mustGetAvailableTimeSlots(t, schedules, options) {
+		if slot.DayOfWeek == "v" {
+			fridaySlots = append(fridaySlots, slot)
+		}
+	}
+	expectedSlots := []models.PlannerEvent{
+		{
+			DayOfWeek:       "v",
+			StartTime:       "08:00",
+			EndTime:         "09:59",
+			UsersAvailable:  1,
+			Attendees:       []string{"1"},
+			Duration:        120,
+			LatestStartTime: "08:00",
+		},
+		{
+			DayOfWeek:       "v",
+			StartTime:       "09:00",
+			EndTime:         "11:59",
+			UsersAvailable:  1,
+			Attendees:       []string{"2"},
+			Duration:        180,
+			LatestStartTime: "10:00",
+		},
+	}
+	if !reflect.DeepEqual(fridaySlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, fridaySlots)
+	}
+}
+
+func TestGetAvailableTimeSlotsWithWeekWrap(t *testing.T) {
+	// Caso de prueba: dos usuarios ocupados toda la semana salvo del sábado 20:00 al domingo 10:00
+	busyDay := []models.TimeBlock{{StartMinute: 0, EndMinute: 1439}}
+	schedule := models.ScheduleModel{
+		Monday:    busyDay,
+		Tuesday:   busyDay,
+		Wednesday: busyDay,
+		Thursday:  busyDay,
+		Friday:    busyDay,
+		Saturday:  []models.TimeBlock{{StartMinute: 0, EndMinute: 1199}},
+		Sunday:    []models.TimeBlock{{StartMinute: 600, EndMinute: 1439}},
+	}
+	first, second := schedule, schedule
+	first.ID, second.ID = "1", "2"
+
+	expectedSlots := []models.PlannerEvent{
+		{
+			DayOfWeek:            "s",
+			StartTime:            "20:00",
+			EndTime:              "09:59",
+			UsersAvailable:       2,
+			Attendees:            []string{"1", "2"},
+			Duration:             840,
+			LatestStartTime:      "07:00",
+			EndDayOfWeek:         "d",
+			LatestStartDayOfWeek: "d",
+		},
+	}
+	actualSlots := mustGetAvailableTimeSlots(t, []models.ScheduleModel{first, second}, models.PlannerOptions{Duration: 180, WeekWrap: true})
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+
+	// Sin la semana circular la ventana se divide en dos días y ninguna parte dura once horas
+	actualSlots = mustGetAvailableTimeSlots(t, []models.ScheduleModel{first, second}, models.PlannerOptions{Duration: 660})
+	if len(actualSlots) != 0 {
+		t.Errorf("ERROR Expected no slots, Got: %v", actualSlots)
+	}
+}
+
+func TestFindMeetingSlotsOnCircularWeek(t *testing.T) {
+	// Caso de prueba: una ventana que continúa del domingo al lunes y un usuario libre toda la semana
+	segments := newTestSegments([]testSegment{
+		{day: "l", start: 120, end: 5000, attendees: []string{"1"}},
+		{day: "l", start: 5000, end: 10080 + 120, attendees: []string{"1", "2"}},
+	})
+	expectedSlots := []models.PlannerEvent{
+		{
+			DayOfWeek:       "l",
+			StartTime:       "02:00",
+			EndTime:         "01:59",
+			UsersAvailable:  1,
+			Attendees:       []string{"1"},
+			Duration:        10080,
+			LatestStartTime: "01:00",
+			EndDayOfWeek:    "l",
+		},
+		{
+			DayOfWeek:            "j",
+			StartTime:            "11:20",
+			EndTime:              "01:59",
+			UsersAvailable:       2,
+			Attendees:            []string{"1", "2"},
+			Duration:             5200,
+			LatestStartTime:      "01:00",
+			EndDayOfWeek:         "l",
+			LatestStartDayOfWeek: "l",
+		},
+	}
+	actualSlots := mustFindMeetingSlots(t, segments, 1, 60, 0, true)
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+}
+
+func TestFindMeetingSlotsWithGranularity(t *testing.T) {
+	// Caso de prueba: una ventana de 09:11 a 11:17 se reduce a 09:15 - 11:15 en una cuadrícula de 15 minutos
+	segments := newTestSegments([]testSegment{
+		{day: "l", start: 0, end: 551, attendees: []string{}},
+		{day: "l", start: 551, end: 677, attendees: []string{"1", "2"}},
+		{day: "l", start: 677, end: 1440, attendees: []string{}},
+	})
+	expectedSlots := []models.PlannerEvent{
+		{
+			DayOfWeek:       "l",
+			StartTime:       "09:15",
+			EndTime:         "11:14",
+			UsersAvailable:  2,
+			Attendees:       []string{"1", "2"},
+			Duration:        120,
+			LatestStartTime: "10:15",
+		},
+	}
+	actualSlots := mustFindMeetingSlots(t, segments, 2, 50, 15, false)
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+
+	// Caso de prueba: la ventana alineada ya no alcanza para una reunión que sí cabía sin alinear
+	actualSlots = mustFindMeetingSlots(t, segments, 2, 121, 15, false)
+	if len(actualSlots) != 0 {
+		t.Errorf("ERROR Expected no slots, Got: %v", actualSlots)
+	}
+}
+
+func TestGetAvailableTimeSlotsWithGranularity(t *testing.T) {
+	// Caso de prueba: sin duración, cada segmento del lunes se reduce hacia adentro a una cuadrícula de 30 minutos
+	schedules := []models.ScheduleModel{
+		{ID: "1", Monday: []models.TimeBlock{{StartMinute: 0, EndMinute: 550}, {StartMinute: 677, EndMinute: 1439}}},
+	}
+
+	var mondaySlots []models.PlannerEvent
+	for _, slot := range mustGetAvailableTimeSlots(t, schedules, models.PlannerOptions{Granularity: 30}) {
+		if slot.DayOfWeek == "l" {
+			mondaySlots = append(mondaySlots, slot)
+		}
+	}
+	expectedSlots := []models.PlannerEvent{
+		{DayOfWeek: "l", StartTime: "00:00", EndTime: "08:59", UsersAvailable: 0, Attendees: []string{}, Duration: 540},
+		{DayOfWeek: "l", StartTime: "09:30", EndTime: "10:59", UsersAvailable: 1, Attendees: []string{"1"}, Duration: 90},
+		{DayOfWeek: "l", StartTime: "11:30", EndTime: "23:59", UsersAvailable: 0, Attendees: []string{}, Duration: 750},
+	}
+	if !reflect.DeepEqual(mondaySlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, mondaySlots)
+	}
+}
+
+func TestFindMeetingSlotsWithTentativeAttendees(t *testing.T) {
+	// Caso de prueba: el usuario 1 tiene un bloque tentativo de 10:00 a 11:00, por lo que solo es tentativo en la ventana del usuario 2
+	segments := newTestSegments([]testSegment{
+		{day: "l", start: 0, end: 600, attendees: []string{"1", "2"}},
+		{day: "l", start: 600, end: 660, attendees: []string{"2"}, tentative: []string{"1"}},
+		{day: "l", start: 660, end: 1440, attendees: []string{"1", "2"}},
+	})
+	expectedSlots := []models.PlannerEvent{
+		{
+			DayOfWeek:       "l",
+			StartTime:       "00:00",
+			EndTime:         "09:59",
+			UsersAvailable:  2,
+			Attendees:       []string{"1", "2"},
+			Duration:        600,
+			LatestStartTime: "09:00",
+		},
+		{
+			DayOfWeek:          "l",
+			StartTime:          "00:00",
+			EndTime:            "23:59",
+			UsersAvailable:     1,
+			Attendees:          []string{"2"},
+			Duration:           1440,
+			LatestStartTime:    "23:00",
+			TentativeAttendees: []string{"1"},
+		},
+		{
+			DayOfWeek:       "l",
+			StartTime:       "11:00",
+			EndTime:         "23:59",
+			UsersAvailable:  2,
+			Attendees:       []string{"1", "2"},
+			Duration:        780,
+			LatestStartTime: "23:00",
+		},
+	}
+	assertMeetingSlots(t, segments, 1, 60, expectedSlots)
+}
+
+// Función auxiliar para aserciones de las ventanas de reunión
+func assertMeetingSlots(t *testing.T, segments []availabilitySegment, minAttendees int, duration int, expectedSlots []models.PlannerEvent) {
+	actualSlots := mustFindMeetingSlots(t, segments, minAttendees, duration, 0, false)
+	if !reflect.DeepEqual(actualSlots, expectedSlots) {
+		t.Errorf("ERROR Expected: %v, Got: %v", expectedSlots, actualSlots)
+	} else {
+		t.Logf("SUCCESS Expected: %v, Got: %v", expectedSlots, actualSlots)
+	}
+}
+
+// Struct para describir un segmento de prueba con los IDs de sus usuarios
+type testSegment struct {
+	day       string
+	start     int
+	end       int
+	attendees []string
+	tentative []string
+}
+
+// Función auxiliar para convertir los segmentos de prueba en segmentos de la barrida, con una tabla de IDs
+// compartida por todos ellos
+func newTestSegments(testSegments []testSegment) []availabilitySegment {
+	var usersId []string
+	for _, segment := range testSegments {
+		usersId = append(usersId, segment.attendees...)
+		usersId = append(usersId, segment.tentative...)
+	}
+	ids := newUserTable(usersId)
+
+	segments := make([]availabilitySegment, len(testSegments))
+	for i, segment := range testSegments {
+		segments[i] = availabilitySegment{
+			day:       segment.day,
+			start:     segment.start,
+			end:       segment.end,
+			attendees: newUserSetOf(ids, segment.attendees),
+			tentative: newUserSetOf(ids, segment.tentative),
+		}
+	}
+	return segments
+}
+
+// Función auxiliar para obtener las ventanas de reunión de los segmentos, fallando la prueba si hay un error
+func mustFindMeetingSlots(t *testing.T, segments []availabilitySegment, minAttendees int, duration int, granularity int, circular bool) []models.PlannerEvent {
+	slots, err := findMeetingSlots(context.Background(), segments, minAttendees, duration, granularity, circular)
+	if err != nil {
+		t.Fatalf("ERROR Unexpected error: %v", err)
+	}
+	return slots
+}